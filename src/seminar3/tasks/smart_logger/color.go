@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode определяет, когда логгер раскрашивает уровни
+type ColorMode int
+
+const (
+	ColorNever ColorMode = iota
+	ColorAlways
+	ColorAuto
+)
+
+func (m ColorMode) String() string {
+	switch m {
+	case ColorNever:
+		return "never"
+	case ColorAlways:
+		return "always"
+	case ColorAuto:
+		return "auto"
+	default:
+		return "unknown"
+	}
+}
+
+// Style описывает ANSI-оформление уровня: код цвета SGR и жирность
+type Style struct {
+	Code string // например "31" или "38;5;208"
+	Bold bool
+}
+
+// Color16 возвращает стиль с одним из базовых цветов терминала (30-37, 90-97)
+func Color16(code int) Style {
+	return Style{Code: fmt.Sprintf("%d", code)}
+}
+
+// Color256 возвращает стиль с цветом из 256-цветной палитры
+func Color256(n uint8) Style {
+	return Style{Code: fmt.Sprintf("38;5;%d", n)}
+}
+
+// Bolded возвращает копию стиля с жирным начертанием
+func (s Style) Bolded() Style {
+	s.Bold = true
+	return s
+}
+
+// sgr собирает escape-последовательность для стиля
+func (s Style) sgr() string {
+	codes := make([]string, 0, 2)
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Code != "" {
+		codes = append(codes, s.Code)
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(codes, ";") + "m"
+}
+
+// ColorTheme сопоставляет уровню логирования его стиль
+type ColorTheme map[Level]Style
+
+// DefaultColorTheme возвращает стандартную тему: зеленый, желтый, красный
+func DefaultColorTheme() ColorTheme {
+	return ColorTheme{
+		Info:  Color16(32),
+		Warn:  Color16(33),
+		Error: Color16(31),
+	}
+}
+
+// detectColor решает, нужен ли цвет для output в режиме ColorAuto.
+// FORCE_COLOR имеет приоритет, затем NO_COLOR, затем проверка на терминал.
+func detectColor(output io.Writer) bool {
+	if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" && force != "false" {
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(output)
+}
+
+// isTerminal проверяет, что output - символьное устройство (TTY)
+func isTerminal(output io.Writer) bool {
+	file, ok := output.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorModeAuto(t *testing.T) {
+	tests := []struct {
+		name       string
+		noColor    string
+		forceColor string
+		expected   bool
+	}{
+		{"not a terminal", "", "", false},
+		{"NO_COLOR set", "1", "", false},
+		{"FORCE_COLOR set", "", "1", true},
+		{"FORCE_COLOR wins over NO_COLOR", "1", "1", true},
+		{"FORCE_COLOR=0 ignored", "", "0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+
+			var buf strings.Builder
+			logger := NewSmartLogger(&buf, "APP")
+			logger.EnableAutoColor()
+			logger.Info("hello")
+
+			assert.Equal(t, tt.expected, strings.Contains(buf.String(), "\033["))
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "log")
+	require.NoError(t, err)
+	defer file.Close()
+
+	assert.False(t, isTerminal(file), "regular file is not a terminal")
+	assert.False(t, isTerminal(&strings.Builder{}))
+}
+
+func TestColorTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "APP")
+	logger.EnableColor()
+	logger.SetColorTheme(ColorTheme{Warn: Color256(208).Bolded()})
+
+	logger.Info("info")
+	logger.Warn("warn")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "\033[32m[INFO]\033[0m", "missing levels fall back to default theme")
+	assert.Contains(t, lines[1], "\033[1;38;5;208m[WARN]\033[0m")
+}
+
+func TestColorNever(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "1")
+
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "APP")
+	logger.SetColorMode(ColorNever)
+	logger.Error("boom")
+
+	assert.NotContains(t, buf.String(), "\033[")
+	assert.Contains(t, buf.String(), "[ERROR]")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entry структурированная запись лога
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Level   Level     `json:"level"`
+	Prefix  string    `json:"prefix"`
+	Message string    `json:"message"`
+}
+
+// EntryWriter приемник, которому логгер передает запись целиком вместо готовой строки
+type EntryWriter interface {
+	WriteEntry(entry Entry) error
+}
+
+// ParseLevel разбирает имя уровня без учета регистра
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "INFO":
+		return Info, nil
+	case "WARN", "WARNING":
+		return Warn, nil
+	case "ERROR":
+		return Error, nil
+	default:
+		return Info, fmt.Errorf("неизвестный уровень логирования: %q", s)
+	}
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
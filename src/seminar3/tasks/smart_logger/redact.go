@@ -0,0 +1,223 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+const redactedPlaceholder = "[REDACTED]"
+
+// RedactMode определяет, чем заменяются найденные секреты
+type RedactMode int
+
+const (
+	RedactMask RedactMode = iota // заменить на [REDACTED]
+	RedactHash                   // заменить на короткий sha256, чтобы значения можно было сопоставлять
+)
+
+// detector ищет один вид чувствительных данных
+type detector struct {
+	name    string
+	pattern *regexp.Regexp
+	group   int               // какую группу заменять; 0 - все совпадение
+	hint    func(string) bool // дешевая проверка, отсекающая regexp на чистых сообщениях
+	valid   func(string) bool // дополнительная проверка совпадения, например Луна для карт
+}
+
+// Redactor вырезает из сообщений секреты до форматирования записи
+type Redactor struct {
+	detectors []detector
+	fields    []string
+	mode      RedactMode
+	builtins  bool
+}
+
+type RedactOption func(*Redactor)
+
+// WithPattern добавляет пользовательское регулярное выражение
+func WithPattern(name string, pattern *regexp.Regexp) RedactOption {
+	return func(r *Redactor) {
+		r.detectors = append(r.detectors, detector{name: name, pattern: pattern})
+	}
+}
+
+// WithFields скрывает значения полей вида key=value, key: value и "key": "value"
+func WithFields(names ...string) RedactOption {
+	return func(r *Redactor) {
+		r.fields = append(r.fields, names...)
+	}
+}
+
+// WithHashing заменяет секреты хешем вместо [REDACTED]
+func WithHashing() RedactOption {
+	return func(r *Redactor) {
+		r.mode = RedactHash
+	}
+}
+
+// WithoutBuiltins отключает встроенные детекторы (email, bearer, карты)
+func WithoutBuiltins() RedactOption {
+	return func(r *Redactor) {
+		r.builtins = false
+	}
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	bearerPattern = regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`)
+	cardPattern   = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+)
+
+func builtinDetectors() []detector {
+	return []detector{
+		{
+			name:    "email",
+			pattern: emailPattern,
+			hint:    func(s string) bool { return strings.IndexByte(s, '@') >= 0 },
+		},
+		{
+			name:    "bearer",
+			pattern: bearerPattern,
+			group:   1,
+			hint:    func(s string) bool { return containsFold(s, "bearer") },
+		},
+		{
+			name:    "card",
+			pattern: cardPattern,
+			hint:    containsDigit,
+			valid:   luhnValid,
+		},
+	}
+}
+
+// NewRedactor создает редактор со встроенными детекторами и заданными опциями
+func NewRedactor(options ...RedactOption) *Redactor {
+	r := &Redactor{builtins: true}
+	for _, option := range options {
+		option(r)
+	}
+
+	var detectors []detector
+	if r.builtins {
+		detectors = builtinDetectors()
+	}
+	if len(r.fields) > 0 {
+		detectors = append(detectors, fieldDetector(r.fields))
+	}
+	r.detectors = append(detectors, r.detectors...)
+
+	return r
+}
+
+// Redact возвращает сообщение с замененными секретами
+func (r *Redactor) Redact(message string) string {
+	for _, d := range r.detectors {
+		if d.hint != nil && !d.hint(message) {
+			continue
+		}
+		message = r.replace(d, message)
+	}
+	return message
+}
+
+func (r *Redactor) replace(d detector, message string) string {
+	matches := d.pattern.FindAllStringSubmatchIndex(message, -1)
+	if len(matches) == 0 {
+		return message
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*d.group], m[2*d.group+1]
+		if start < 0 {
+			continue
+		}
+		secret := message[start:end]
+		if d.valid != nil && !d.valid(secret) {
+			continue
+		}
+		b.WriteString(message[last:start])
+		b.WriteString(r.mask(secret))
+		last = end
+	}
+	b.WriteString(message[last:])
+
+	return b.String()
+}
+
+func (r *Redactor) mask(secret string) string {
+	if r.mode == RedactHash {
+		sum := sha256.Sum256([]byte(secret))
+		return "[sha256:" + hex.EncodeToString(sum[:6]) + "]"
+	}
+	return redactedPlaceholder
+}
+
+// fieldDetector ищет значения перечисленных полей без учета регистра
+func fieldDetector(names []string) detector {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	pattern := regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)"?\s*[=:]\s*"?([^\s",;&]+)`)
+
+	return detector{
+		name:    "field",
+		pattern: pattern,
+		group:   1,
+		hint: func(s string) bool {
+			return strings.ContainsAny(s, "=:")
+		},
+	}
+}
+
+// containsFold ищет ASCII-подстроку в нижнем регистре без учета регистра и без
+// выделения памяти, в отличие от strings.ToLower на каждом сообщении
+func containsFold(s, lower string) bool {
+	for i := 0; i+len(lower) <= len(s); i++ {
+		j := 0
+		for j < len(lower) && s[i+j]|0x20 == lower[j] {
+			j++
+		}
+		if j == len(lower) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDigit(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid проверяет номер карты по алгоритму Луна, игнорируя пробелы и дефисы
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+	digits := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
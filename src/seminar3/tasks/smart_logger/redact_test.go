@@ -0,0 +1,123 @@
+package main
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor(t *testing.T) {
+	t.Parallel()
+
+	redactor := NewRedactor(
+		WithFields("password", "api_key"),
+		WithPattern("ssh", regexp.MustCompile(`ssh-rsa \S+`)),
+	)
+
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{"clean message", "Сервер запущен на порту 8080", "Сервер запущен на порту 8080"},
+		{"email", "письмо для john.doe@example.com отправлено", "письмо для [REDACTED] отправлено"},
+		{"bearer token", "Authorization: Bearer abc.DEF-123", "Authorization: Bearer [REDACTED]"},
+		{"bearer upper case", "AUTHORIZATION: BEARER abc.DEF-123", "AUTHORIZATION: BEARER [REDACTED]"},
+		{"card number", "карта 4111 1111 1111 1111 списана", "карта [REDACTED] списана"},
+		{"not a card", "заказ 1234567890123 создан", "заказ 1234567890123 создан"},
+		{"field key=value", "login ok password=qwerty user=bob", "login ok password=[REDACTED] user=bob"},
+		{"field json", `{"api_key": "sk_live_1", "id": 1}`, `{"api_key": "[REDACTED]", "id": 1}`},
+		{"field case insensitive", "PASSWORD: hunter2", "PASSWORD: [REDACTED]"},
+		{"custom pattern", "ключ ssh-rsa AAAAB3Nza добавлен", "ключ [REDACTED] добавлен"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, redactor.Redact(tt.message))
+		})
+	}
+}
+
+// без t.Parallel: AllocsPerRun нельзя вызывать в параллельном тесте
+func TestContainsFold(t *testing.T) {
+	assert.True(t, containsFold("Authorization: Bearer x", "bearer"))
+	assert.True(t, containsFold("BEARER", "bearer"))
+	assert.True(t, containsFold("токен bEaReR x", "bearer"))
+	assert.False(t, containsFold("bear", "bearer"))
+	assert.False(t, containsFold("Сервер запущен", "bearer"))
+
+	message := strings.Repeat("Сервер запущен на порту 8080 ", 10)
+	allocs := testing.AllocsPerRun(100, func() { containsFold(message, "bearer") })
+	assert.Zero(t, allocs, "hint runs on every message and must not allocate")
+}
+
+func TestRedactorHashing(t *testing.T) {
+	t.Parallel()
+
+	redactor := NewRedactor(WithHashing())
+
+	first := redactor.Redact("user a@b.io")
+	second := redactor.Redact("again a@b.io")
+
+	assert.NotContains(t, first, "a@b.io")
+	assert.Regexp(t, `\[sha256:[0-9a-f]{12}\]`, first)
+	assert.Equal(t, strings.TrimPrefix(first, "user "), strings.TrimPrefix(second, "again "),
+		"same secret must hash to the same value")
+}
+
+func TestRedactorWithoutBuiltins(t *testing.T) {
+	t.Parallel()
+
+	redactor := NewRedactor(WithoutBuiltins(), WithFields("token"))
+
+	assert.Equal(t, "a@b.io token=[REDACTED]", redactor.Redact("a@b.io token=xyz"))
+}
+
+func TestSmartLoggerRedaction(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "APP")
+	logger.SetRedactor(NewRedactor())
+
+	logger.Info("email %s", "admin@example.com")
+	logger.Write([]byte("Bearer secret-token\n"))
+
+	assert.NotContains(t, buf.String(), "admin@example.com")
+	assert.NotContains(t, buf.String(), "secret-token")
+	assert.Equal(t, 2, strings.Count(buf.String(), redactedPlaceholder))
+}
+
+func BenchmarkLogWithoutRedactor(b *testing.B) {
+	logger := NewSmartLogger(io.Discard, "BENCH")
+	for i := 0; i < b.N; i++ {
+		logger.Info("request handled in %d ms for user %s", 42, "bob")
+	}
+}
+
+func BenchmarkLogRedactorCleanMessage(b *testing.B) {
+	logger := NewSmartLogger(io.Discard, "BENCH")
+	logger.SetRedactor(NewRedactor(WithFields("password")))
+	for i := 0; i < b.N; i++ {
+		logger.Info("request handled in %d ms for user %s", 42, "bob")
+	}
+}
+
+func BenchmarkLogRedactorWithSecrets(b *testing.B) {
+	logger := NewSmartLogger(io.Discard, "BENCH")
+	logger.SetRedactor(NewRedactor(WithFields("password")))
+	for i := 0; i < b.N; i++ {
+		logger.Info("user %s password=%s card %s", "bob@example.com", "qwerty", "4111 1111 1111 1111")
+	}
+}
+
+func BenchmarkRedactHashing(b *testing.B) {
+	redactor := NewRedactor(WithHashing())
+	for i := 0; i < b.N; i++ {
+		redactor.Redact("Authorization: Bearer abc.DEF-123 for bob@example.com")
+	}
+}
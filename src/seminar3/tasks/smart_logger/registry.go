@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rootName имя корневого логгера в конфигурации и admin API
+const rootName = "root"
+
+// Registry хранит именованные логгеры, образующие иерархию через точку: app -> app.db -> app.db.pool.
+// Логгер без явно заданного уровня или приемника наследует их от ближайшего предка.
+type Registry struct {
+	mu      sync.Mutex
+	levels  map[string]Level
+	outputs map[string]io.Writer
+	loggers map[string]*SmartLogger
+}
+
+// NewRegistry создает реестр с корневыми приемником и уровнем
+func NewRegistry(output io.Writer, level Level) *Registry {
+	return &Registry{
+		levels:  map[string]Level{"": level},
+		outputs: map[string]io.Writer{"": output},
+		loggers: make(map[string]*SmartLogger),
+	}
+}
+
+// Logger возвращает логгер с именем name, создавая его при первом обращении.
+// Имя логгера используется как его префикс.
+func (r *Registry) Logger(name string) *SmartLogger {
+	name = normalizeName(name)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if logger, ok := r.loggers[name]; ok {
+		return logger
+	}
+
+	logger := NewSmartLogger(r.effectiveOutput(name), name)
+	logger.SetLevel(r.effectiveLevel(name))
+	r.loggers[name] = logger
+	return logger
+}
+
+// SetLevel задает уровень для name и всех потомков, у которых нет своего уровня
+func (r *Registry) SetLevel(name string, level Level) {
+	name = normalizeName(name)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.levels[name] = level
+	r.propagate(name)
+}
+
+// ResetLevel убирает явный уровень, и name снова наследует уровень предка.
+// Уровень корня сбросить нельзя.
+func (r *Registry) ResetLevel(name string) {
+	name = normalizeName(name)
+	if name == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.levels, name)
+	r.propagate(name)
+}
+
+// SetOutput задает приемник для name и потомков без своего приемника
+func (r *Registry) SetOutput(name string, output io.Writer) {
+	name = normalizeName(name)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.outputs[name] = output
+	r.propagate(name)
+}
+
+// EffectiveLevel возвращает уровень, с которым сейчас работает логгер name
+func (r *Registry) EffectiveLevel(name string) Level {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.effectiveLevel(normalizeName(name))
+}
+
+func (r *Registry) effectiveLevel(name string) Level {
+	for n := name; ; n = parentName(n) {
+		if level, ok := r.levels[n]; ok {
+			return level
+		}
+		if n == "" {
+			return Info
+		}
+	}
+}
+
+func (r *Registry) effectiveOutput(name string) io.Writer {
+	for n := name; ; n = parentName(n) {
+		if output, ok := r.outputs[n]; ok {
+			return output
+		}
+		if n == "" {
+			return nil
+		}
+	}
+}
+
+// propagate пересчитывает уровни и приемники всех логгеров поддерева name
+func (r *Registry) propagate(name string) {
+	for loggerName, logger := range r.loggers {
+		if !inSubtree(loggerName, name) {
+			continue
+		}
+		logger.SetLevel(r.effectiveLevel(loggerName))
+		logger.setOutput(r.effectiveOutput(loggerName))
+	}
+}
+
+// LevelInfo описывает уровень логгера для admin API
+type LevelInfo struct {
+	Name     string `json:"name"`
+	Level    Level  `json:"level"`
+	Explicit bool   `json:"explicit"`
+}
+
+// Levels возвращает уровни всех известных логгеров и явно настроенных узлов
+func (r *Registry) Levels() []LevelInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := map[string]bool{"": true}
+	for name := range r.levels {
+		names[name] = true
+	}
+	for name := range r.loggers {
+		names[name] = true
+	}
+
+	result := make([]LevelInfo, 0, len(names))
+	for name := range names {
+		_, explicit := r.levels[name]
+		result = append(result, LevelInfo{
+			Name:     displayName(name),
+			Level:    r.effectiveLevel(name),
+			Explicit: explicit,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// RegistryConfig конфигурация уровней: имя логгера -> уровень
+type RegistryConfig struct {
+	Levels map[string]Level `json:"levels"`
+}
+
+// ApplyConfig заменяет все явные уровни уровнями из конфигурации.
+// Корень, не упомянутый в конфигурации, сохраняет текущий уровень.
+func (r *Registry) ApplyConfig(config RegistryConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rootLevel := r.levels[""]
+	r.levels = map[string]Level{"": rootLevel}
+	for name, level := range config.Levels {
+		r.levels[normalizeName(name)] = level
+	}
+	r.propagate("")
+}
+
+// LoadConfigFile читает JSON-конфигурацию вида {"levels": {"app.db": "WARN"}} и применяет ее
+func (r *Registry) LoadConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения конфигурации логгеров: %w", err)
+	}
+
+	var config RegistryConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("ошибка разбора конфигурации логгеров: %w", err)
+	}
+
+	r.ApplyConfig(config)
+	return nil
+}
+
+// WatchConfigFile перечитывает файл при изменении времени модификации.
+// Ошибки перезагрузки пишутся в корневой логгер. Возвращает функцию остановки.
+func (r *Registry) WatchConfigFile(path string, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var lastMod time.Time
+		for {
+			if info, err := os.Stat(path); err == nil && !info.ModTime().Equal(lastMod) {
+				lastMod = info.ModTime()
+				if err := r.LoadConfigFile(path); err != nil {
+					r.Logger("").Error("%v", err)
+				}
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// ServeHTTP admin API уровней:
+//
+//	GET                         - список логгеров и уровней
+//	PUT/POST ?name=app.db&level=WARN - задать уровень поддереву
+//	DELETE ?name=app.db         - вернуть наследование уровня
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	switch req.Method {
+	case http.MethodGet:
+		// только чтение
+	case http.MethodPut, http.MethodPost:
+		if !query.Has("name") {
+			http.Error(w, "не указан параметр name", http.StatusBadRequest)
+			return
+		}
+		level, err := ParseLevel(query.Get("level"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.SetLevel(query.Get("name"), level)
+	case http.MethodDelete:
+		if !query.Has("name") {
+			http.Error(w, "не указан параметр name", http.StatusBadRequest)
+			return
+		}
+		r.ResetLevel(query.Get("name"))
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+		http.Error(w, "метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(r.Levels())
+}
+
+func normalizeName(name string) string {
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if name == rootName {
+		return ""
+	}
+	return name
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return rootName
+	}
+	return name
+}
+
+func parentName(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+func inSubtree(name, root string) bool {
+	return root == "" || name == root || strings.HasPrefix(name, root+".")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryInheritsLevel(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry(nil, Info)
+	pool := registry.Logger("app.db.pool")
+	httpLogger := registry.Logger("app.http")
+
+	registry.SetLevel("app.db", Error)
+
+	assert.Equal(t, Error, registry.EffectiveLevel("app.db.pool"))
+	assert.Equal(t, Info, registry.EffectiveLevel("app.http"))
+	assert.Equal(t, Error, pool.level)
+	assert.Equal(t, Info, httpLogger.level)
+
+	registry.SetLevel("app", Warn)
+	assert.Equal(t, Error, registry.EffectiveLevel("app.db.pool"), "explicit child level wins")
+	assert.Equal(t, Warn, registry.EffectiveLevel("app.http"))
+
+	registry.ResetLevel("app.db")
+	assert.Equal(t, Warn, pool.level)
+}
+
+func TestRegistryInheritsOutput(t *testing.T) {
+	t.Parallel()
+
+	var rootBuf, dbBuf strings.Builder
+	registry := NewRegistry(&rootBuf, Info)
+	registry.SetOutput("app.db", &dbBuf)
+
+	registry.Logger("app").Info("app message")
+	registry.Logger("app.db.pool").Info("pool message")
+
+	assert.Contains(t, rootBuf.String(), "app [INFO]: app message")
+	assert.Contains(t, dbBuf.String(), "app.db.pool [INFO]: pool message")
+	assert.NotContains(t, rootBuf.String(), "pool message")
+}
+
+func TestRegistrySameLogger(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry(nil, Info)
+	assert.Same(t, registry.Logger("app.db"), registry.Logger(" app.db. "))
+	assert.Same(t, registry.Logger(""), registry.Logger("root"))
+}
+
+func TestRegistryAdminHTTP(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry(nil, Info)
+	registry.Logger("app.db")
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/loggers?name=app&level=error", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, Error, registry.EffectiveLevel("app.db"))
+
+	var levels []LevelInfo
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &levels))
+	assert.Contains(t, levels, LevelInfo{Name: "app", Level: Error, Explicit: true})
+	assert.Contains(t, levels, LevelInfo{Name: "app.db", Level: Error, Explicit: false})
+
+	rec = httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/loggers?name=app", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, Info, registry.EffectiveLevel("app.db"))
+
+	rec = httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/loggers?name=app&level=loud", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRegistryConfigFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "loggers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"levels": {"root": "WARN", "app.db": "ERROR"}}`), 0o644))
+
+	registry := NewRegistry(nil, Info)
+	registry.SetLevel("app.http", Info)
+	require.NoError(t, registry.LoadConfigFile(path))
+
+	assert.Equal(t, Warn, registry.EffectiveLevel("app"))
+	assert.Equal(t, Error, registry.EffectiveLevel("app.db.pool"))
+	assert.Equal(t, Warn, registry.EffectiveLevel("app.http"), "levels missing from config are reset")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"levels": {"app": "oops"}}`), 0o644))
+	assert.Error(t, registry.LoadConfigFile(path))
+}
+
+func TestRegistryWatchConfigFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "loggers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"levels": {"app": "WARN"}}`), 0o644))
+
+	registry := NewRegistry(nil, Info)
+	stop := registry.WatchConfigFile(path, 10*time.Millisecond)
+	defer stop()
+
+	assert.Eventually(t, func() bool { return registry.EffectiveLevel("app") == Warn }, time.Second, 5*time.Millisecond)
+
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.WriteFile(path, []byte(`{"levels": {"app": "ERROR"}}`), 0o644))
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	assert.Eventually(t, func() bool { return registry.EffectiveLevel("app") == Error }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Filter отбирает записи из RingBuffer; нулевые поля не ограничивают выборку
+type Filter struct {
+	MinLevel Level
+	Prefix   string
+	Since    time.Time
+	Until    time.Time
+	Limit    int // сколько последних записей вернуть
+}
+
+func (f Filter) match(entry Entry) bool {
+	if entry.Level < f.MinLevel {
+		return false
+	}
+	if f.Prefix != "" && entry.Prefix != f.Prefix {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// RingBuffer хранит последние N записей в памяти.
+// Подходит как output для NewSmartLogger и как http.Handler для /debug/logs.
+type RingBuffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &RingBuffer{entries: make([]Entry, capacity)}
+}
+
+// WriteEntry сохраняет запись, вытесняя самую старую при переполнении
+func (rb *RingBuffer) WriteEntry(entry Entry) error {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.entries[rb.next] = entry
+	rb.next = (rb.next + 1) % len(rb.entries)
+	if rb.next == 0 {
+		rb.full = true
+	}
+	return nil
+}
+
+// Write позволяет использовать буфер как обычный io.Writer: строка сохраняется как INFO
+func (rb *RingBuffer) Write(p []byte) (n int, err error) {
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   Info,
+		Message: strings.TrimSpace(string(p)),
+	}
+	if err := rb.WriteEntry(entry); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (rb *RingBuffer) Len() int {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if rb.full {
+		return len(rb.entries)
+	}
+	return rb.next
+}
+
+// Entries возвращает подходящие под фильтр записи от старых к новым
+func (rb *RingBuffer) Entries(filter Filter) []Entry {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	var ordered []Entry
+	if rb.full {
+		ordered = append(ordered, rb.entries[rb.next:]...)
+	}
+	ordered = append(ordered, rb.entries[:rb.next]...)
+
+	result := make([]Entry, 0, len(ordered))
+	for _, entry := range ordered {
+		if filter.match(entry) {
+			result = append(result, entry)
+		}
+	}
+
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[len(result)-filter.Limit:]
+	}
+	return result
+}
+
+// WriteTo выводит все записи в текстовом виде, например при панике
+func (rb *RingBuffer) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, entry := range rb.Entries(Filter{}) {
+		n, err := io.WriteString(w, formatLine(entry, fmt.Sprintf("[%s]", entry.Level)))
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ServeHTTP отдает записи в JSON.
+// Параметры: level, prefix, since и until (RFC 3339), limit.
+func (rb *RingBuffer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := parseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rb.Entries(filter))
+}
+
+func parseFilter(query url.Values) (Filter, error) {
+	var filter Filter
+
+	if v := query.Get("level"); v != "" {
+		level, err := ParseLevel(v)
+		if err != nil {
+			return filter, err
+		}
+		filter.MinLevel = level
+	}
+
+	filter.Prefix = query.Get("prefix")
+
+	for key, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := query.Get(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, fmt.Errorf("некорректный параметр %s: %w", key, err)
+			}
+			*target = t
+		}
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return filter, fmt.Errorf("некорректный параметр limit: %q", v)
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func messages(entries []Entry) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Message
+	}
+	return result
+}
+
+func TestRingBufferOverwritesOldest(t *testing.T) {
+	t.Parallel()
+
+	ring := NewRingBuffer(3)
+	logger := NewSmartLogger(ring, "APP")
+	for _, msg := range []string{"1", "2", "3", "4", "5"} {
+		logger.Info("%s", msg)
+	}
+
+	assert.Equal(t, 3, ring.Len())
+	assert.Equal(t, []string{"3", "4", "5"}, messages(ring.Entries(Filter{})))
+	assert.Equal(t, 5, logger.GetLogCount())
+}
+
+func TestRingBufferFilter(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ring := NewRingBuffer(10)
+	ring.WriteEntry(Entry{Time: base, Level: Info, Prefix: "APP", Message: "a"})
+	ring.WriteEntry(Entry{Time: base.Add(time.Minute), Level: Warn, Prefix: "DB", Message: "b"})
+	ring.WriteEntry(Entry{Time: base.Add(2 * time.Minute), Level: Error, Prefix: "APP", Message: "c"})
+	ring.WriteEntry(Entry{Time: base.Add(3 * time.Minute), Level: Info, Prefix: "DB", Message: "d"})
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"no filter", Filter{}, []string{"a", "b", "c", "d"}},
+		{"min level", Filter{MinLevel: Warn}, []string{"b", "c"}},
+		{"prefix", Filter{Prefix: "DB"}, []string{"b", "d"}},
+		{"time range", Filter{Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)}, []string{"b", "c"}},
+		{"limit keeps newest", Filter{Limit: 2}, []string{"c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, messages(ring.Entries(tt.filter)))
+		})
+	}
+}
+
+func TestRingBufferHTTP(t *testing.T) {
+	t.Parallel()
+
+	ring := NewRingBuffer(10)
+	logger := NewSmartLogger(ring, "HTTP")
+	logger.Info("started")
+	logger.Error("failed")
+
+	t.Run("json with level filter", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ring.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs?level=error", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var entries []Entry
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+		require.Len(t, entries, 1)
+		assert.Equal(t, Error, entries[0].Level)
+		assert.Equal(t, "HTTP", entries[0].Prefix)
+		assert.Contains(t, rec.Body.String(), `"level":"ERROR"`)
+	})
+
+	t.Run("bad parameter", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ring.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs?since=yesterday", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ring.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/logs", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestRingBufferWriteTo(t *testing.T) {
+	t.Parallel()
+
+	ring := NewRingBuffer(2)
+	logger := NewSmartLogger(ring, "DUMP")
+	logger.Warn("first")
+
+	var buf strings.Builder
+	_, err := ring.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "DUMP [WARN]: first")
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Formatter превращает запись в строку для приемника
+type Formatter interface {
+	Format(entry Entry) string
+}
+
+// Facility источник сообщения по RFC 5424
+type Facility int
+
+const (
+	FacilityUser   Facility = 1
+	FacilityDaemon Facility = 3
+	FacilityLocal0 Facility = 16
+	FacilityLocal7 Facility = 23
+)
+
+// Severity уровни важности syslog, которые использует логгер
+const (
+	severityError   = 3
+	severityWarning = 4
+	severityNotice  = 5
+	severityInfo    = 6
+)
+
+// syslogSeverity сопоставляет уровню логгера severity syslog
+func syslogSeverity(level Level) int {
+	switch level {
+	case Info:
+		return severityInfo
+	case Warn:
+		return severityWarning
+	case Error:
+		return severityError
+	default:
+		return severityNotice
+	}
+}
+
+// SyslogFormatter форматирует записи по RFC 5424:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG
+//
+// APP-NAME берется из префикса логгера.
+type SyslogFormatter struct {
+	Facility Facility
+	Hostname string
+	ProcID   string
+	MsgID    string
+}
+
+// NewSyslogFormatter создает форматтер с facility user, именем хоста и PID процесса
+func NewSyslogFormatter() *SyslogFormatter {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = ""
+	}
+	return &SyslogFormatter{
+		Facility: FacilityUser,
+		Hostname: hostname,
+		ProcID:   strconv.Itoa(os.Getpid()),
+	}
+}
+
+func (f *SyslogFormatter) Format(entry Entry) string {
+	priority := int(f.Facility)*8 + syslogSeverity(entry.Level)
+
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s - %s",
+		priority,
+		entry.Time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		headerField(f.Hostname, 255),
+		headerField(entry.Prefix, 48),
+		headerField(f.ProcID, 128),
+		headerField(f.MsgID, 32),
+		entry.Message,
+	)
+}
+
+// headerField приводит поле заголовка к печатному ASCII без пробелов; пустое поле - "-"
+func headerField(value string, maxLen int) string {
+	var b strings.Builder
+	for i := 0; i < len(value) && b.Len() < maxLen; i++ {
+		if c := value[i]; c >= 33 && c <= 126 {
+			b.WriteByte(c)
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+// defaultSyslogSockets локальные сокеты syslog; /dev/log обслуживает и journald
+var defaultSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogWriter приемник, отправляющий записи в syslog по unix-сокету, UDP или TCP.
+type SyslogWriter struct {
+	mu        sync.Mutex
+	network   string
+	addr      string
+	conn      net.Conn
+	formatter Formatter
+}
+
+// DialSyslog подключается к syslog. network: "unixgram", "unix", "udp" или "tcp".
+// Пустые network и addr означают локальный демон (/dev/log).
+// formatter nil означает NewSyslogFormatter().
+func DialSyslog(network, addr string, formatter Formatter) (*SyslogWriter, error) {
+	if formatter == nil {
+		formatter = NewSyslogFormatter()
+	}
+	sw := &SyslogWriter{network: network, addr: addr, formatter: formatter}
+
+	if err := sw.connect(); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+func (sw *SyslogWriter) connect() error {
+	if sw.network != "" || sw.addr != "" {
+		conn, err := net.Dial(sw.network, sw.addr)
+		if err != nil {
+			return fmt.Errorf("ошибка подключения к syslog %s://%s: %w", sw.network, sw.addr, err)
+		}
+		sw.conn = conn
+		return nil
+	}
+
+	var errs []error
+	for _, path := range defaultSyslogSockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.Dial(network, path)
+			if err == nil {
+				sw.network, sw.addr, sw.conn = network, path, conn
+				return nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	return fmt.Errorf("локальный syslog недоступен: %w", errors.Join(errs...))
+}
+
+// WriteEntry отправляет запись; при обрыве соединения делает одну попытку переподключения
+func (sw *SyslogWriter) WriteEntry(entry Entry) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	message := sw.frame(sw.formatter.Format(entry))
+
+	if sw.conn != nil {
+		if _, err := sw.conn.Write(message); err == nil {
+			return nil
+		}
+		sw.conn.Close()
+		sw.conn = nil
+	}
+
+	if err := sw.connect(); err != nil {
+		return err
+	}
+	_, err := sw.conn.Write(message)
+	return err
+}
+
+// Write позволяет использовать приемник как io.Writer: строка отправляется как INFO
+func (sw *SyslogWriter) Write(p []byte) (n int, err error) {
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   Info,
+		Message: strings.TrimSpace(string(p)),
+	}
+	if err := sw.WriteEntry(entry); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// frame кадрирует сообщение: датаграммы отправляются как есть,
+// TCP - с длиной впереди, потоковый unix-сокет - с переводом строки
+func (sw *SyslogWriter) frame(message string) []byte {
+	switch sw.network {
+	case "tcp", "tcp4", "tcp6":
+		return []byte(strconv.Itoa(len(message)) + " " + message)
+	case "unix":
+		return []byte(message + "\n")
+	default:
+		return []byte(message)
+	}
+}
+
+func (sw *SyslogWriter) Close() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if sw.conn == nil {
+		return nil
+	}
+	err := sw.conn.Close()
+	sw.conn = nil
+	return err
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFormatter() *SyslogFormatter {
+	return &SyslogFormatter{Facility: FacilityLocal0, Hostname: "host1", ProcID: "42"}
+}
+
+func TestSyslogFormatter(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2025, 3, 1, 10, 20, 30, 123456000, time.UTC)
+	formatter := testFormatter()
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		expected string
+	}{
+		{
+			"info",
+			Entry{Time: ts, Level: Info, Prefix: "APP", Message: "started"},
+			"<134>1 2025-03-01T10:20:30.123456Z host1 APP 42 - - started",
+		},
+		{
+			"warn",
+			Entry{Time: ts, Level: Warn, Prefix: "DB", Message: "slow"},
+			"<132>1 2025-03-01T10:20:30.123456Z host1 DB 42 - - slow",
+		},
+		{
+			"error with empty prefix",
+			Entry{Time: ts, Level: Error, Message: "boom"},
+			"<131>1 2025-03-01T10:20:30.123456Z host1 - 42 - - boom",
+		},
+		{
+			"app-name sanitized",
+			Entry{Time: ts, Level: Info, Prefix: "my app", Message: "x"},
+			"<134>1 2025-03-01T10:20:30.123456Z host1 myapp 42 - - x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, formatter.Format(tt.entry))
+		})
+	}
+}
+
+func TestSyslogWriterUDP(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := DialSyslog("udp", listener.LocalAddr().String(), testFormatter())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	NewSmartLogger(sink, "APP").Warn("disk %d%%", 91)
+
+	buf := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	require.NoError(t, err)
+
+	message := string(buf[:n])
+	assert.True(t, strings.HasPrefix(message, "<132>1 "), message)
+	assert.True(t, strings.HasSuffix(message, " host1 APP 42 - - disk 91%"), message)
+}
+
+func TestSyslogWriterTCPOctetCounting(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		var messages []string
+		for len(messages) < 2 {
+			lengthStr, err := reader.ReadString(' ')
+			if err != nil {
+				break
+			}
+			length, _ := strconv.Atoi(strings.TrimSpace(lengthStr))
+			msg := make([]byte, length)
+			if _, err := io.ReadFull(reader, msg); err != nil {
+				break
+			}
+			messages = append(messages, string(msg))
+		}
+		received <- messages
+	}()
+
+	sink, err := DialSyslog("tcp", listener.Addr().String(), testFormatter())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	logger := NewSmartLogger(sink, "HTTP")
+	logger.Info("first")
+	logger.Error("second")
+
+	select {
+	case messages := <-received:
+		require.Len(t, messages, 2)
+		assert.Contains(t, messages[0], "<134>1 ")
+		assert.True(t, strings.HasSuffix(messages[0], "HTTP 42 - - first"))
+		assert.Contains(t, messages[1], "<131>1 ")
+	case <-time.After(2 * time.Second):
+		t.Fatal("listener did not receive messages")
+	}
+}
+
+func TestSyslogWriterUnixgram(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp("", "syslog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.sock")
+
+	listener, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := DialSyslog("unixgram", path, testFormatter())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	_, err = sink.Write([]byte("plain write\n"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(buf[:n]), " host1 - 42 - - plain write"))
+}
+
+func TestSyslogDialError(t *testing.T) {
+	t.Parallel()
+
+	_, err := DialSyslog("unixgram", filepath.Join(t.TempDir(), "missing.sock"), nil)
+	assert.Error(t, err)
+}
+
+func TestSmartLoggerSetFormatter(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "APP")
+	logger.SetFormatter(testFormatter())
+	logger.Error("failed")
+
+	assert.Regexp(t, `^<131>1 \S+ host1 APP 42 - - failed\n$`, buf.String())
+}
@@ -0,0 +1,92 @@
+package alert
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// Alert срабатывание правила для города или, если Resolved, его снятие
+type Alert struct {
+	Rule     Rule      `json:"rule"`
+	City     string    `json:"city"`
+	Value    float64   `json:"value"`
+	Resolved bool      `json:"resolved"`
+	Time     time.Time `json:"time"`
+}
+
+// Engine проверяет правила и помнит, какие из них уже сработали.
+// Повторное срабатывание не порождает оповещение, пока условие не снимется.
+type Engine struct {
+	rules []Rule
+
+	mu     sync.Mutex
+	active map[activeKey]bool
+}
+
+type activeKey struct {
+	rule int
+	city string
+}
+
+func NewEngine(rules []Rule) (*Engine, error) {
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("правило %d (%s): %w", i+1, rule.Title(), err)
+		}
+	}
+	return &Engine{rules: rules, active: make(map[activeKey]bool)}, nil
+}
+
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// Cities города, явно перечисленные в правилах, без повторов
+func (e *Engine) Cities() []string {
+	seen := make(map[string]bool)
+	var cities []string
+	for _, rule := range e.rules {
+		for _, city := range rule.Cities {
+			if key := cityKey(city); !seen[key] {
+				seen[key] = true
+				cities = append(cities, city)
+			}
+		}
+	}
+	return cities
+}
+
+// Evaluate проверяет погоду в городе city и возвращает новые срабатывания и снятия.
+// city - запрошенный город: провайдер может вернуть в data.City другое написание.
+func (e *Engine) Evaluate(city string, data *domain.WeatherData, at time.Time) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var alerts []Alert
+	for i, rule := range e.rules {
+		if !rule.Applies(city) {
+			continue
+		}
+
+		// без значения поля состояние правила не меняется
+		value, ok := rule.Field.Value(data)
+		if !ok {
+			continue
+		}
+
+		key := activeKey{rule: i, city: cityKey(city)}
+		matched := rule.Operator.compare(value, rule.Threshold)
+		switch {
+		case matched && !e.active[key]:
+			e.active[key] = true
+			alerts = append(alerts, Alert{Rule: rule, City: city, Value: value, Time: at})
+		case !matched && e.active[key]:
+			delete(e.active, key)
+			alerts = append(alerts, Alert{Rule: rule, City: city, Value: value, Resolved: true, Time: at})
+		}
+	}
+	return alerts
+}
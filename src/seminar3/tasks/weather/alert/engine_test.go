@@ -0,0 +1,212 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+var now = time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+
+func TestEngineDeduplicatesUntilCleared(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine([]Rule{
+		{Name: "frost", Field: FieldTemperature, Operator: Less, Threshold: -15, Cities: []string{"Moscow"}},
+		{Name: "storm", Field: FieldWindSpeed, Operator: Greater, Threshold: 50},
+	})
+	require.NoError(t, err)
+
+	alerts := engine.Evaluate("Moscow", &domain.WeatherData{Temperature: -20, WindSpeed: 60}, now)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "frost", alerts[0].Rule.Name)
+	assert.Equal(t, -20.0, alerts[0].Value)
+	assert.False(t, alerts[0].Resolved)
+
+	alerts = engine.Evaluate("moscow", &domain.WeatherData{Temperature: -22, WindSpeed: 10}, now)
+	require.Len(t, alerts, 1, "frost is still active, storm cleared")
+	assert.Equal(t, "storm", alerts[0].Rule.Name)
+	assert.True(t, alerts[0].Resolved)
+
+	alerts = engine.Evaluate("London", &domain.WeatherData{Temperature: -20, WindSpeed: 10}, now)
+	assert.Empty(t, alerts, "frost rule is only for Moscow")
+
+	alerts = engine.Evaluate("Moscow", &domain.WeatherData{Temperature: -5}, now)
+	require.Len(t, alerts, 1)
+	assert.True(t, alerts[0].Resolved)
+
+	alerts = engine.Evaluate("Moscow", &domain.WeatherData{Temperature: -16}, now)
+	require.Len(t, alerts, 1, "fires again after clearing")
+	assert.False(t, alerts[0].Resolved)
+}
+
+func TestEngineKeepsStateWithoutValue(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine([]Rule{{Field: FieldPressure, Operator: Less, Threshold: 990}})
+	require.NoError(t, err)
+
+	require.Len(t, engine.Evaluate("Moscow", &domain.WeatherData{Pressure: domain.Ptr(980.0)}, now), 1)
+	assert.Empty(t, engine.Evaluate("Moscow", &domain.WeatherData{}, now), "missing pressure neither fires nor clears")
+	assert.Len(t, engine.Evaluate("Moscow", &domain.WeatherData{Pressure: domain.Ptr(1000.0)}, now), 1)
+}
+
+func TestEngineResolvesOnZero(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine([]Rule{
+		{Name: "rain", Field: FieldPrecipitation, Operator: Greater, Threshold: 5},
+		{Name: "overcast", Field: FieldCloudCover, Operator: Greater, Threshold: 80},
+	})
+	require.NoError(t, err)
+
+	alerts := engine.Evaluate("Moscow", &domain.WeatherData{Precipitation: domain.Ptr(7.5), CloudCover: domain.Ptr(100)}, now)
+	require.Len(t, alerts, 2)
+	assert.False(t, alerts[0].Resolved)
+
+	alerts = engine.Evaluate("Moscow", &domain.WeatherData{Precipitation: domain.Ptr(0.0), CloudCover: domain.Ptr(0)}, now)
+	require.Len(t, alerts, 2, "zero is a reading, so both alerts resolve")
+	for _, alert := range alerts {
+		assert.True(t, alert.Resolved)
+		assert.Equal(t, 0.0, alert.Value)
+	}
+}
+
+func TestNewEngineValidatesRules(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEngine([]Rule{{Field: "snow", Operator: Greater}})
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	engine, err := NewEngine([]Rule{
+		{Field: FieldTemperature, Operator: Less, Cities: []string{"Moscow", "London"}},
+		{Field: FieldWindSpeed, Operator: Greater, Cities: []string{"london", "Paris"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Moscow", "London", "Paris"}, engine.Cities())
+}
+
+// weatherFunc провайдер погоды из функции
+type weatherFunc func(ctx context.Context, city string) (*domain.WeatherData, error)
+
+func (f weatherFunc) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	return f(ctx, city)
+}
+
+// collector запоминает полученные оповещения
+type collector struct {
+	mu     sync.Mutex
+	alerts []Alert
+	err    error
+}
+
+func (c *collector) Notify(ctx context.Context, alert Alert) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.alerts = append(c.alerts, alert)
+	return c.err
+}
+
+func TestWatcherPoll(t *testing.T) {
+	t.Parallel()
+
+	temperature := -20.0
+	provider := weatherFunc(func(ctx context.Context, city string) (*domain.WeatherData, error) {
+		if city == "Atlantis" {
+			return nil, client.ErrCityNotFound
+		}
+		return &domain.WeatherData{City: city, Temperature: temperature}, nil
+	})
+	engine, err := NewEngine([]Rule{{Field: FieldTemperature, Operator: Less, Threshold: -15}})
+	require.NoError(t, err)
+
+	notifier := &collector{}
+	var errs []error
+	watcher := NewWatcher(client.NewWeatherService(provider), engine, notifier, []string{"Moscow", "Atlantis"},
+		WithClock(func() time.Time { return now }),
+		WithErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+
+	alerts := watcher.Poll(context.Background())
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "Moscow", alerts[0].City)
+	assert.Equal(t, now, alerts[0].Time)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], client.ErrCityNotFound)
+
+	assert.Empty(t, watcher.Poll(context.Background()), "repeated alert is suppressed")
+
+	temperature = -10
+	notifier.err = errors.New("webhook down")
+	alerts = watcher.Poll(context.Background())
+	require.Len(t, alerts, 1)
+	assert.True(t, alerts[0].Resolved)
+	assert.Len(t, notifier.alerts, 2)
+	assert.ErrorContains(t, errors.Join(errs...), "webhook down")
+}
+
+// pausedProvider провайдер, которому сервер велел подождать
+type pausedProvider struct {
+	weatherFunc
+	until time.Time
+}
+
+func (p pausedProvider) RateLimit() (client.RateLimitState, bool) {
+	return client.RateLimitState{PausedUntil: p.until, Wait: p.until.Sub(now)}, true
+}
+
+func TestWatcherPollSkipsWhenPaused(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	provider := pausedProvider{
+		weatherFunc: func(ctx context.Context, city string) (*domain.WeatherData, error) {
+			calls++
+			return &domain.WeatherData{City: city, Temperature: -20}, nil
+		},
+		until: now.Add(time.Minute),
+	}
+	engine, err := NewEngine([]Rule{{Field: FieldTemperature, Operator: Less, Threshold: -15}})
+	require.NoError(t, err)
+
+	var errs []error
+	watcher := NewWatcher(client.NewWeatherService(provider), engine, &collector{}, []string{"Moscow"},
+		WithErrorHandler(func(err error) { errs = append(errs, err) }))
+
+	assert.Empty(t, watcher.Poll(context.Background()))
+	assert.Zero(t, calls, "no requests while the server asks to wait")
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], client.ErrRateLimited)
+}
+
+func TestWatcherRunStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	polls := make(chan string, 10)
+	provider := weatherFunc(func(ctx context.Context, city string) (*domain.WeatherData, error) {
+		polls <- city
+		return &domain.WeatherData{City: city}, nil
+	})
+	engine, err := NewEngine([]Rule{{Field: FieldTemperature, Operator: Less, Threshold: -15}})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := NewWatcher(client.NewWeatherService(provider), engine, &collector{}, []string{"Moscow"},
+		WithInterval(time.Millisecond))
+
+	done := make(chan error)
+	go func() { done <- watcher.Run(ctx) }()
+
+	<-polls
+	<-polls
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
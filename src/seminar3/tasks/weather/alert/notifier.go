@@ -0,0 +1,132 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// Notifier доставляет оповещения: в консоль, на webhook, в файл
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Notifiers рассылает оповещение всем получателям; сбой одного не мешает остальным
+type Notifiers []Notifier
+
+func (n Notifiers) Notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriterNotifier печатает оповещения строкой текста на языке printer
+type WriterNotifier struct {
+	mu      sync.Mutex
+	w       io.Writer
+	printer *i18n.Printer
+}
+
+func NewWriterNotifier(w io.Writer, printer *i18n.Printer) *WriterNotifier {
+	return &WriterNotifier{w: w, printer: printer}
+}
+
+func (n *WriterNotifier) Notify(ctx context.Context, alert Alert) error {
+	key := i18n.AlertTriggered
+	if alert.Resolved {
+		key = i18n.AlertResolved
+	}
+	line := n.printer.T(key, alert.Time.Format(time.TimeOnly), alert.City, alert.Rule.Title(), alert.Rule.Field, alert.Value)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, err := fmt.Fprintln(n.w, line); err != nil {
+		return fmt.Errorf("ошибка вывода оповещения: %w", err)
+	}
+	return nil
+}
+
+// WebhookNotifier отправляет оповещение POST-запросом с JSON телом Alert
+type WebhookNotifier struct {
+	client *http.Client
+	url    string
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		client: &http.Client{Timeout: 10 * time.Second},
+		url:    url,
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации оповещения: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "WeatherCLI/1.0 (educational project)")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook ответил %s", resp.Status)
+	}
+	return nil
+}
+
+// FileNotifier дописывает оповещения в файл по JSON-объекту на строку
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Notify(ctx context.Context, alert Alert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации оповещения: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла оповещений: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("ошибка записи оповещения: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("ошибка записи оповещения: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+var frost = Alert{
+	Rule:  Rule{Name: "frost", Field: FieldTemperature, Operator: Less, Threshold: -15},
+	City:  "Moscow",
+	Value: -17.5,
+	Time:  now,
+}
+
+func TestWriterNotifier(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	notifier := NewWriterNotifier(&out, i18n.NewPrinter(i18n.English))
+
+	resolved := frost
+	resolved.Resolved = true
+	require.NoError(t, notifier.Notify(context.Background(), frost))
+	require.NoError(t, notifier.Notify(context.Background(), resolved))
+
+	assert.Equal(t, "🚨 [09:00:00] Moscow: frost (temperature = -17.5)\n"+
+		"✅ [09:00:00] Moscow: cleared frost (temperature = -17.5)\n", out.String())
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Parallel()
+
+	var received Alert
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		contentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		if json.Unmarshal(body, &received) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	require.NoError(t, NewWebhookNotifier(server.URL).Notify(context.Background(), frost))
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, frost.Rule, received.Rule)
+	assert.Equal(t, -17.5, received.Value)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.ErrorContains(t, NewWebhookNotifier(failing.URL).Notify(context.Background(), frost), "500")
+}
+
+func TestFileNotifier(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+	notifier := NewFileNotifier(path)
+	require.NoError(t, notifier.Notify(context.Background(), frost))
+	require.NoError(t, notifier.Notify(context.Background(), frost))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var alert Alert
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &alert))
+	assert.Equal(t, "Moscow", alert.City)
+}
+
+func TestNotifiersContinueAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	failing := &collector{err: errors.New("down")}
+	working := &collector{}
+
+	err := Notifiers{failing, working}.Notify(context.Background(), frost)
+	assert.ErrorContains(t, err, "down")
+	assert.Len(t, working.alerts, 1)
+}
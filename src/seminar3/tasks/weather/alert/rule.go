@@ -0,0 +1,237 @@
+// Package alert проверяет погоду по декларативным правилам, например
+// "temperature < -15" для списка городов, и рассылает оповещения.
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// ErrInvalidRule правило не удалось разобрать или в нем неизвестное поле или оператор
+var ErrInvalidRule = errors.New("некорректное правило")
+
+// Field показатель WeatherData, по которому срабатывает правило.
+// Значения в метрических единицах: °C, %, км/ч, гПа, км, мм.
+type Field string
+
+const (
+	FieldTemperature   Field = "temperature"
+	FieldFeelsLike     Field = "feels_like"
+	FieldHumidity      Field = "humidity"
+	FieldWindSpeed     Field = "wind_speed"
+	FieldPressure      Field = "pressure"
+	FieldVisibility    Field = "visibility"
+	FieldUVIndex       Field = "uv_index"
+	FieldCloudCover    Field = "cloud_cover"
+	FieldPrecipitation Field = "precipitation"
+)
+
+// Value значение поля в data. Расширенного поля может не быть (провайдер
+// его не прислал), и тогда ok = false: правило такое значение не проверяет.
+// Ноль - обычное значение: осадки кончились, и правило "precipitation > 5" снимается.
+func (f Field) Value(data *domain.WeatherData) (value float64, ok bool) {
+	switch f {
+	case FieldTemperature:
+		return data.Temperature, true
+	case FieldFeelsLike:
+		return data.FeelsLike, true
+	case FieldHumidity:
+		return float64(data.Humidity), true
+	case FieldWindSpeed:
+		return data.WindSpeed, true
+	case FieldPressure:
+		return optional(data.Pressure)
+	case FieldVisibility:
+		return optional(data.Visibility)
+	case FieldUVIndex:
+		return optional(data.UVIndex)
+	case FieldCloudCover:
+		return optional(data.CloudCover)
+	case FieldPrecipitation:
+		return optional(data.Precipitation)
+	default:
+		return 0, false
+	}
+}
+
+// optional значение расширенного поля; nil - поля нет
+func optional[T int | float64](v *T) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return float64(*v), true
+}
+
+func (f Field) valid() bool {
+	switch f {
+	case FieldTemperature, FieldFeelsLike, FieldHumidity, FieldWindSpeed, FieldPressure,
+		FieldVisibility, FieldUVIndex, FieldCloudCover, FieldPrecipitation:
+		return true
+	default:
+		return false
+	}
+}
+
+// Operator сравнение значения поля с порогом
+type Operator string
+
+const (
+	Less         Operator = "<"
+	LessEqual    Operator = "<="
+	Greater      Operator = ">"
+	GreaterEqual Operator = ">="
+	Equal        Operator = "=="
+	NotEqual     Operator = "!="
+)
+
+func (o Operator) compare(value, threshold float64) bool {
+	switch o {
+	case Less:
+		return value < threshold
+	case LessEqual:
+		return value <= threshold
+	case Greater:
+		return value > threshold
+	case GreaterEqual:
+		return value >= threshold
+	case Equal:
+		return value == threshold
+	case NotEqual:
+		return value != threshold
+	default:
+		return false
+	}
+}
+
+func (o Operator) valid() bool {
+	switch o {
+	case Less, LessEqual, Greater, GreaterEqual, Equal, NotEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rule условие на одно поле; пустой Cities означает все города
+type Rule struct {
+	Name      string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Field     Field    `json:"field" yaml:"field"`
+	Operator  Operator `json:"operator" yaml:"operator"`
+	Threshold float64  `json:"threshold" yaml:"threshold"`
+	Cities    []string `json:"cities,omitempty" yaml:"cities,omitempty"`
+}
+
+func (r Rule) Validate() error {
+	if !r.Field.valid() {
+		return fmt.Errorf("%w: неизвестное поле %q", ErrInvalidRule, r.Field)
+	}
+	if !r.Operator.valid() {
+		return fmt.Errorf("%w: неизвестный оператор %q", ErrInvalidRule, r.Operator)
+	}
+	return nil
+}
+
+// Title имя правила, а без него - само условие
+func (r Rule) Title() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Condition()
+}
+
+// Condition условие в виде "temperature < -15"
+func (r Rule) Condition() string {
+	return fmt.Sprintf("%s %s %s", r.Field, r.Operator, strconv.FormatFloat(r.Threshold, 'f', -1, 64))
+}
+
+// Applies относится ли правило к городу; регистр и лишние пробелы не учитываются
+func (r Rule) Applies(city string) bool {
+	if len(r.Cities) == 0 {
+		return true
+	}
+	for _, c := range r.Cities {
+		if cityKey(c) == cityKey(city) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match проверяет условие и возвращает значение поля, на котором оно проверялось
+func (r Rule) Match(data *domain.WeatherData) (value float64, matched bool) {
+	value, ok := r.Field.Value(data)
+	if !ok {
+		return value, false
+	}
+	return value, r.Operator.compare(value, r.Threshold)
+}
+
+var ruleRe = regexp.MustCompile(`^\s*([a-z_]+)\s*(<=|>=|==|!=|<|>)\s*(-?\d+(?:\.\d+)?)\s*(?:@\s*(.+))?$`)
+
+// ParseRule разбирает правило из строки "поле оператор порог[@город,город]",
+// например "wind_speed > 50" или "temperature < -15 @ Moscow, Novosibirsk"
+func ParseRule(value string) (Rule, error) {
+	match := ruleRe.FindStringSubmatch(value)
+	if match == nil {
+		return Rule{}, fmt.Errorf("%w %q: ожидается \"поле оператор порог[@город,...]\"", ErrInvalidRule, value)
+	}
+
+	threshold, err := strconv.ParseFloat(match[3], 64)
+	if err != nil {
+		return Rule{}, fmt.Errorf("%w %q: %w", ErrInvalidRule, value, err)
+	}
+	rule := Rule{Field: Field(match[1]), Operator: Operator(match[2]), Threshold: threshold}
+	for _, city := range strings.Split(match[4], ",") {
+		if city = strings.TrimSpace(city); city != "" {
+			rule.Cities = append(rule.Cities, city)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// rulesFile формат файла правил; JSON тоже подходит, он читается как YAML
+type rulesFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadRules читает правила из YAML-файла вида
+//
+//	rules:
+//	  - name: frost
+//	    field: temperature
+//	    operator: "<"
+//	    threshold: -15
+//	    cities: [Moscow, Novosibirsk]
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать правила: %w", err)
+	}
+
+	var file rulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: ошибка разбора %s: %w", ErrInvalidRule, path, err)
+	}
+	for i, rule := range file.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("правило %d (%s): %w", i+1, rule.Title(), err)
+		}
+	}
+	return file.Rules, nil
+}
+
+func cityKey(city string) string {
+	return strings.ToLower(strings.Join(strings.Fields(city), " "))
+}
@@ -0,0 +1,101 @@
+package alert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected Rule
+		wantErr  bool
+	}{
+		{input: "temperature < -15", expected: Rule{Field: FieldTemperature, Operator: Less, Threshold: -15}},
+		{input: "wind_speed>50", expected: Rule{Field: FieldWindSpeed, Operator: Greater, Threshold: 50}},
+		{input: "humidity >= 90.5 @ Moscow, New York", expected: Rule{
+			Field: FieldHumidity, Operator: GreaterEqual, Threshold: 90.5, Cities: []string{"Moscow", "New York"},
+		}},
+		{input: "uv_index != 0", expected: Rule{Field: FieldUVIndex, Operator: NotEqual, Threshold: 0}},
+		{input: "snow > 10", wantErr: true},
+		{input: "temperature => 10", wantErr: true},
+		{input: "temperature < cold", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := ParseRule(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRule)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rule)
+		})
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	t.Parallel()
+
+	data := &domain.WeatherData{Temperature: -17.5, WindSpeed: 30}
+
+	value, matched := Rule{Field: FieldTemperature, Operator: Less, Threshold: -15}.Match(data)
+	assert.True(t, matched)
+	assert.Equal(t, -17.5, value)
+
+	_, matched = Rule{Field: FieldWindSpeed, Operator: Greater, Threshold: 50}.Match(data)
+	assert.False(t, matched)
+
+	_, matched = Rule{Field: FieldVisibility, Operator: Less, Threshold: 1}.Match(data)
+	assert.False(t, matched, "missing extended field does not match")
+
+	rule := Rule{Field: FieldTemperature, Operator: Less, Threshold: -15, Cities: []string{"New York"}}
+	assert.True(t, rule.Applies(" new  york"))
+	assert.False(t, rule.Applies("Moscow"))
+	assert.Equal(t, "temperature < -15", rule.Title())
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - name: frost
+    field: temperature
+    operator: "<"
+    threshold: -15
+    cities: [Moscow, Novosibirsk]
+  - field: wind_speed
+    operator: ">"
+    threshold: 50
+`), 0o644))
+
+	rules, err := LoadRules(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Name: "frost", Field: FieldTemperature, Operator: Less, Threshold: -15, Cities: []string{"Moscow", "Novosibirsk"}},
+		{Field: FieldWindSpeed, Operator: Greater, Threshold: 50},
+	}, rules)
+
+	bad := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("rules:\n  - field: temperature\n    operator: '~'\n"), 0o644))
+	_, err = LoadRules(bad)
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = LoadRules(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"example/src/seminar3/tasks/weather/client"
+)
+
+const defaultWatchInterval = 10 * time.Minute
+
+// Watcher периодически запрашивает погоду в городах и проверяет правила
+type Watcher struct {
+	service  *client.WeatherService
+	engine   *Engine
+	notifier Notifier
+	cities   []string
+	interval time.Duration
+	onError  func(error)
+	now      func() time.Time
+}
+
+type WatchOption func(*Watcher)
+
+// WithInterval период опроса, по умолчанию 10 минут
+func WithInterval(interval time.Duration) WatchOption {
+	return func(w *Watcher) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// WithErrorHandler получает ошибки запросов и доставки оповещений; опрос при них не прерывается
+func WithErrorHandler(onError func(error)) WatchOption {
+	return func(w *Watcher) {
+		w.onError = onError
+	}
+}
+
+// WithClock подменяет текущее время, в основном для тестов
+func WithClock(now func() time.Time) WatchOption {
+	return func(w *Watcher) {
+		w.now = now
+	}
+}
+
+func NewWatcher(service *client.WeatherService, engine *Engine, notifier Notifier, cities []string, options ...WatchOption) *Watcher {
+	w := &Watcher{
+		service:  service,
+		engine:   engine,
+		notifier: notifier,
+		cities:   cities,
+		interval: defaultWatchInterval,
+		onError:  func(error) {},
+		now:      time.Now,
+	}
+	for _, option := range options {
+		option(w)
+	}
+	return w
+}
+
+// Run опрашивает города сразу и затем каждый интервал, пока не отменен ctx
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll один опрос всех городов; возвращает разосланные оповещения
+func (w *Watcher) Poll(ctx context.Context) []Alert {
+	// сервер попросил подождать: пропускаем опрос, а не копим очередь запросов
+	if state, ok := w.service.RateLimit(); ok && !state.PausedUntil.IsZero() {
+		w.onError(fmt.Errorf("опрос пропущен: %w, следующий запрос возможен в %s", client.ErrRateLimited, state.PausedUntil.Format(time.TimeOnly)))
+		return nil
+	}
+
+	var alerts []Alert
+	for _, result := range w.service.GetWeatherBatch(ctx, w.cities) {
+		if result.Err != nil {
+			w.onError(fmt.Errorf("%s: %w", result.City, result.Err))
+			continue
+		}
+
+		for _, alert := range w.engine.Evaluate(result.City, result.Data, w.now()) {
+			if err := w.notifier.Notify(ctx, alert); err != nil {
+				w.onError(fmt.Errorf("оповещение %s для %s: %w", alert.Rule.Title(), alert.City, err))
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
@@ -0,0 +1,303 @@
+// Package cassette записывает HTTP-обмен с сервисами погоды в файл и воспроизводит
+// его без сети. Настоящие ответы снимаются один раз в режиме записи, после чего
+// тесты работают с ними детерминированно.
+package cassette
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNoInteraction в кассете нет (или больше нет) ответа на запрос
+var ErrNoInteraction = errors.New("cassette: нет записанного ответа")
+
+// EnvMode переменная окружения с режимом для тестов: WEATHER_CASSETTE=record go test ./...
+const EnvMode = "WEATHER_CASSETTE"
+
+// Mode режим работы транспорта
+type Mode string
+
+const (
+	// ModeReplay только воспроизведение; запрос без записи - ошибка ErrNoInteraction
+	ModeReplay Mode = "replay"
+	// ModeRecord все запросы идут в сеть, кассета перезаписывается при Save
+	ModeRecord Mode = "record"
+	// ModeAuto воспроизведение, если файл кассеты есть, иначе запись
+	ModeAuto Mode = "auto"
+)
+
+// ParseMode разбирает режим; пустая строка - ModeReplay
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return ModeReplay, nil
+	case ModeReplay, ModeRecord, ModeAuto:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("cassette: неизвестный режим %q: ожидается replay, record или auto", value)
+	}
+}
+
+// Request записанный запрос
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Response записанный ответ; тело хранится текстом, сервисы погоды отвечают JSON
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Interaction пара запрос-ответ
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette содержимое файла кассеты
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Transport http.RoundTripper, который записывает или воспроизводит ответы.
+// Одинаковые запросы воспроизводятся в порядке записи, поэтому кассета
+// с ответами 503, 503, 200 проверяет повторные попытки.
+type Transport struct {
+	path        string
+	mode        Mode
+	transport   http.RoundTripper
+	ignoreQuery map[string]bool
+	header      []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+type Option func(*Transport)
+
+// WithMode режим работы, по умолчанию ModeReplay
+func WithMode(mode Mode) Option {
+	return func(t *Transport) {
+		t.mode = mode
+	}
+}
+
+// WithTransport транспорт для записи, по умолчанию http.DefaultTransport
+func WithTransport(transport http.RoundTripper) Option {
+	return func(t *Transport) {
+		t.transport = transport
+	}
+}
+
+// WithIgnoredQuery параметры запроса, которые не сохраняются в кассету и не
+// участвуют в сопоставлении, например API ключ appid
+func WithIgnoredQuery(params ...string) Option {
+	return func(t *Transport) {
+		for _, param := range params {
+			t.ignoreQuery[param] = true
+		}
+	}
+}
+
+// WithHeaders заголовки ответа, которые сохраняются в кассету, по умолчанию
+// Content-Type и Retry-After; остальные (даты, cookies) только засоряют фикстуры
+func WithHeaders(names ...string) Option {
+	return func(t *Transport) {
+		t.header = names
+	}
+}
+
+// New открывает кассету path. В режиме воспроизведения файл обязан существовать.
+func New(path string, options ...Option) (*Transport, error) {
+	t := &Transport{
+		path:        path,
+		mode:        ModeReplay,
+		transport:   http.DefaultTransport,
+		ignoreQuery: make(map[string]bool),
+		header:      []string{"Content-Type", "Retry-After"},
+	}
+	for _, option := range options {
+		option(t)
+	}
+
+	if t.mode == ModeRecord {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && t.mode == ModeAuto {
+		t.mode = ModeRecord
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cassette: не удалось прочитать %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &t.cassette); err != nil {
+		return nil, fmt.Errorf("cassette: ошибка разбора %s: %w", path, err)
+	}
+	t.mode = ModeReplay
+	t.used = make([]bool, len(t.cassette.Interactions))
+	return t, nil
+}
+
+// Mode фактический режим: ModeAuto превращается в ModeReplay или ModeRecord
+func (t *Transport) Mode() Mode {
+	return t.mode
+}
+
+// Interactions записанные или загруженные пары запрос-ответ
+func (t *Transport) Interactions() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Interaction(nil), t.cassette.Interactions...)
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	request := Request{Method: req.Method, URL: t.normalize(req.URL)}
+	if t.mode == ModeRecord {
+		return t.record(req, request)
+	}
+	return t.replay(req, request)
+}
+
+func (t *Transport) replay(req *http.Request, request Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] || interaction.Request != request {
+			continue
+		}
+		t.used[i] = true
+		return interaction.Response.toHTTP(req), nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, request.Method, request.URL)
+}
+
+func (t *Transport) record(req *http.Request, request Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header)
+	for _, name := range t.header {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	response := Response{StatusCode: resp.StatusCode, Header: header, Body: string(body)}
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{Request: request, Response: response})
+	t.mu.Unlock()
+
+	// вызывающему отдаем ответ целиком, как от настоящего сервера, но уже
+	// распакованным: так же он будет выглядеть при воспроизведении
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// readBody читает тело ответа; сжатое gzip распаковывается, чтобы в кассете был текст
+func readBody(resp *http.Response) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: ошибка распаковки ответа: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: ошибка чтения ответа: %w", err)
+	}
+	return body, nil
+}
+
+// Save записывает кассету на диск, если она в режиме записи; в режиме
+// воспроизведения ничего не делает. Удобно вызывать из t.Cleanup.
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+
+	// тела ответов - HTML и JSON, экранирование < и > сделало бы фикстуры нечитаемыми
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	t.mu.Lock()
+	err := encoder.Encode(t.cassette)
+	t.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("cassette: ошибка сериализации: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("cassette: не удалось создать каталог: %w", err)
+	}
+	if err := os.WriteFile(t.path, data.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("cassette: не удалось записать %s: %w", t.path, err)
+	}
+	return nil
+}
+
+// normalize URL без игнорируемых параметров, с параметрами в алфавитном порядке
+func (t *Transport) normalize(u *url.URL) string {
+	normalized := *u
+	query := u.Query()
+	for param := range t.ignoreQuery {
+		query.Del(param)
+	}
+	// Encode сортирует параметры по имени
+	normalized.RawQuery = query.Encode()
+	normalized.Fragment = ""
+	return normalized.String()
+}
+
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,182 @@
+package cassette
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// get выполняет GET через транспорт и возвращает код и тело ответа
+func get(t *testing.T, transport http.RoundTripper, url string) (int, string, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body), nil
+}
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value       string
+		expected    Mode
+		expectError bool
+	}{
+		{value: "", expected: ModeReplay},
+		{value: "replay", expected: ModeReplay},
+		{value: " Record", expected: ModeRecord},
+		{value: "auto", expected: ModeAuto},
+		{value: "rewind", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			mode, err := ParseMode(tt.value)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"city": "<Moscow>", "q": "` + r.URL.Query().Get("q") + `"}`))
+	}))
+	path := filepath.Join(t.TempDir(), "nested", "moscow.json")
+
+	recorder, err := New(path, WithMode(ModeRecord), WithIgnoredQuery("appid"))
+	require.NoError(t, err)
+	status, _, err := get(t, recorder, server.URL+"/weather?q=Moscow&appid=secret")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	status, body, err := get(t, recorder, server.URL+"/weather?appid=secret&q=Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"city": "<Moscow>", "q": "Moscow"}`, body, "recorder passes the response through")
+	require.NoError(t, recorder.Save())
+	server.Close()
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(saved), "secret", "ignored query and unlisted headers are not saved")
+	assert.Contains(t, string(saved), `<Moscow>`)
+
+	player, err := New(path, WithIgnoredQuery("appid"))
+	require.NoError(t, err)
+	assert.Equal(t, ModeReplay, player.Mode())
+	require.Len(t, player.Interactions(), 2)
+
+	// ключ в запросе может быть другим: он не участвует в сопоставлении
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/weather?q=Moscow&appid=other", nil)
+	require.NoError(t, err)
+	resp, err := player.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	assert.Empty(t, resp.Header.Get("Set-Cookie"))
+
+	status, body, err = get(t, player, server.URL+"/weather?q=Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"city": "<Moscow>", "q": "Moscow"}`, body)
+
+	_, _, err = get(t, player, server.URL+"/weather?q=Moscow")
+	assert.ErrorIs(t, err, ErrNoInteraction, "recorded responses are used once")
+	_, _, err = get(t, player, server.URL+"/weather?q=London")
+	assert.ErrorIs(t, err, ErrNoInteraction)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "replay does not touch the network")
+}
+
+func TestNewModes(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	_, err := New(missing)
+	assert.Error(t, err, "replay requires the cassette")
+
+	auto, err := New(missing, WithMode(ModeAuto))
+	require.NoError(t, err)
+	assert.Equal(t, ModeRecord, auto.Mode(), "auto records a missing cassette")
+
+	broken := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(broken, []byte("{"), 0o644))
+	_, err = New(broken, WithMode(ModeAuto))
+	assert.Error(t, err)
+
+	player, err := New(broken, WithMode(ModeRecord))
+	require.NoError(t, err, "record does not read the old cassette")
+	require.NoError(t, player.Save())
+	replayed, err := New(broken, WithMode(ModeAuto))
+	require.NoError(t, err)
+	assert.Equal(t, ModeReplay, replayed.Mode())
+	assert.Empty(t, replayed.Interactions())
+}
+
+func TestRecordGzip(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{"city": "Moscow"}`))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "gzip.json")
+	recorder, err := New(path, WithMode(ModeRecord))
+	require.NoError(t, err)
+
+	// Accept-Encoding задан явно, поэтому http.Transport тело не распаковывает
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := recorder.RoundTrip(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"city": "Moscow"}`, string(body))
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+
+	require.NoError(t, recorder.Save())
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), `{\"city\": \"Moscow\"}`, "cassette stores the decompressed body")
+}
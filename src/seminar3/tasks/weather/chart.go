@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"example/src/seminar3/tasks/weather/chart"
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/config"
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// runChart рисует график прогноза или истории:
+// weather chart --forecast 3 --out moscow.png Moscow
+// weather chart --since 168h --out week.svg Moscow London
+func runChart(args []string) {
+	flags := flag.NewFlagSet("chart", flag.ExitOnError)
+	out := flags.String("out", "chart.png", "файл графика, формат по расширению: .png или .svg")
+	width := flags.Int("width", 1000, "ширина изображения в пикселях")
+	height := flags.Int("height", 700, "высота изображения в пикселях")
+	forecastDays := flags.Int("forecast", 0, "график прогноза на N дней (1-3) вместо истории")
+	flags.String("provider", defaults.Provider, "источники прогноза через запятую по приоритету")
+	flags.Duration("cache-ttl", defaults.CacheTTL, "сколько хранить ответы в кеше на диске (0 - без кеша)")
+	flags.String("history-file", defaults.HistoryFile, "файл истории (по умолчанию в каталоге настроек пользователя)")
+	period := addPeriodFlags(flags)
+	flags.String("units", defaults.Units, "единицы измерения: metric, imperial или si")
+	flags.String("lang", defaults.Lang, "язык подписей: ru или en (по умолчанию из LANG)")
+	configPath := addConfigFlags(flags)
+	flags.Parse(args)
+
+	cfg := loadConfig(flags, *configPath)
+
+	units, err := domain.ParseUnits(cfg.Units)
+	if err != nil {
+		fatal(err)
+	}
+	if _, err := chart.FormatFromPath(*out); err != nil {
+		fatal(err)
+	}
+
+	cities := flags.Args()
+	var series []chart.Series
+	title := printer.T(i18n.ChartHistoryTitle)
+
+	if *forecastDays > 0 {
+		if len(cities) == 0 {
+			fatal(errors.New("укажите хотя бы один город для прогноза"))
+		}
+		title = printer.T(i18n.ChartForecastTitle)
+		series = forecastSeries(cfg, cities, *forecastDays)
+	} else {
+		entries, err := readHistory(cfg.HistoryFile, period, cities)
+		if err != nil {
+			fatal(err)
+		}
+		series = chart.FromHistory(entries)
+	}
+
+	c := chart.New(printer, units, chart.WithSize(*width, *height), chart.WithTitle(title))
+	if err := c.Save(*out, series...); err != nil {
+		if errors.Is(err, chart.ErrNoData) {
+			fmt.Println(printer.T(i18n.HistoryEmpty))
+			os.Exit(exitError)
+		}
+		fatal(err)
+	}
+	fmt.Println(printer.T(i18n.ChartSaved, *out))
+}
+
+// forecastSeries запрашивает прогноз для каждого города по очереди
+func forecastSeries(cfg *config.Config, cities []string, days int) []chart.Series {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cities, err := normalizeLocations(cities)
+	if err != nil {
+		fail(err)
+	}
+	provider, err := newCompositeProvider(strings.Split(cfg.Provider, ","), false, cfg.CacheTTL, providerOptions(cfg, false)...)
+	if err != nil {
+		fatal(err)
+	}
+	service := client.NewWeatherService(provider)
+
+	series := make([]chart.Series, 0, len(cities))
+	for _, city := range cities {
+		forecast, err := service.GetForecast(ctx, city, days)
+		if err != nil {
+			fail(err)
+		}
+		series = append(series, chart.FromForecast(forecast))
+	}
+	return series
+}
@@ -0,0 +1,268 @@
+// Package chart рисует графики температуры и влажности по прогнозу
+// или по истории наблюдений в PNG и SVG.
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+	"gonum.org/v1/plot/vg/vgsvg"
+
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/history"
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// Форматы изображений
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+const (
+	defaultWidth  = 1000 // пикселей
+	defaultHeight = 700
+	// dpi пересчет пикселей в единицы vg; совпадает с разрешением vgimg по умолчанию
+	dpi = vgimg.DefaultDPI
+)
+
+// ErrNoData нечего рисовать: ни в одном ряду нет точек
+var ErrNoData = errors.New("нет данных для графика")
+
+// Point одно значение ряда; температура в °C, как в WeatherData
+type Point struct {
+	Time        time.Time
+	Temperature float64
+	FeelsLike   float64
+	Humidity    float64
+}
+
+// Series точки одного города по возрастанию времени
+type Series struct {
+	City   string
+	Points []Point
+}
+
+// FromForecast ряд из почасового прогноза
+func FromForecast(forecast *domain.Forecast) Series {
+	series := Series{City: forecast.City}
+	for _, day := range forecast.Days {
+		for _, hour := range day.Hourly {
+			series.Points = append(series.Points, Point{
+				Time:        hour.Time,
+				Temperature: hour.Temperature,
+				FeelsLike:   hour.FeelsLike,
+				Humidity:    float64(hour.Humidity),
+			})
+		}
+	}
+	return series
+}
+
+// FromHistory ряды по городам из записей истории
+func FromHistory(entries []history.Entry) []Series {
+	groups := history.ByCity(entries)
+	series := make([]Series, len(groups))
+	for i, group := range groups {
+		series[i].City = group[0].Data.City
+		for _, entry := range group {
+			series[i].Points = append(series[i].Points, Point{
+				Time:        entry.Time,
+				Temperature: entry.Data.Temperature,
+				FeelsLike:   entry.Data.FeelsLike,
+				Humidity:    float64(entry.Data.Humidity),
+			})
+		}
+	}
+	return series
+}
+
+// Chart рисует два графика друг под другом: температуру с ощущаемой
+// температурой и влажность, по линии на город
+type Chart struct {
+	printer *i18n.Printer
+	units   domain.Units
+	title   string
+	width   int
+	height  int
+}
+
+type Option func(*Chart)
+
+// WithSize размер изображения в пикселях
+func WithSize(width, height int) Option {
+	return func(c *Chart) {
+		if width > 0 && height > 0 {
+			c.width, c.height = width, height
+		}
+	}
+}
+
+// WithTitle заголовок над графиком температуры
+func WithTitle(title string) Option {
+	return func(c *Chart) {
+		c.title = title
+	}
+}
+
+func New(printer *i18n.Printer, units domain.Units, options ...Option) *Chart {
+	c := &Chart{
+		printer: printer,
+		units:   units,
+		width:   defaultWidth,
+		height:  defaultHeight,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// FormatFromPath определяет формат изображения по расширению файла
+func FormatFromPath(path string) (string, error) {
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext {
+	case FormatPNG, FormatSVG:
+		return ext, nil
+	default:
+		return "", fmt.Errorf("неизвестный формат графика %q: ожидается файл .png или .svg", filepath.Ext(path))
+	}
+}
+
+// Save рисует график в файл; формат определяется по расширению. График
+// рисуется в память, поэтому при ошибке файл не создается и не портится.
+func (c *Chart) Save(path string, series ...Series) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := c.Render(&buf, format, series...); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("ошибка записи графика: %w", err)
+	}
+	return nil
+}
+
+// Render рисует график в w в формате format
+func (c *Chart) Render(w io.Writer, format string, series ...Series) error {
+	first, ok := firstPoint(series)
+	if !ok {
+		return ErrNoData
+	}
+
+	width := vg.Length(c.width) * vg.Inch / dpi
+	height := vg.Length(c.height) * vg.Inch / dpi
+
+	var canvas vg.CanvasWriterTo
+	switch format {
+	case FormatPNG:
+		canvas = vgimg.PngCanvas{Canvas: vgimg.NewWith(vgimg.UseWH(width, height), vgimg.UseDPI(dpi))}
+	case FormatSVG:
+		canvas = vgsvg.New(width, height)
+	default:
+		return fmt.Errorf("неизвестный формат графика %q: ожидается png или svg", format)
+	}
+
+	// время подписываем в часовом поясе данных: для прогноза это пояс города
+	ticks := plot.TimeTicks{
+		Format: c.printer.T(i18n.ChartTimeLayout),
+		Time:   plot.UnixTimeIn(first.Time.Location()),
+	}
+
+	temperature := plot.New()
+	temperature.Title.Text = c.title
+	temperature.Y.Label.Text = c.printer.T(i18n.ChartTemperature, c.units.TemperatureSymbol())
+	temperature.X.Tick.Marker = ticks
+	temperature.Add(plotter.NewGrid())
+	temperature.Legend.Top = true
+
+	humidity := plot.New()
+	humidity.Y.Label.Text = c.printer.T(i18n.ChartHumidity)
+	humidity.Y.Min, humidity.Y.Max = 0, 100
+	humidity.X.Tick.Marker = ticks
+	humidity.Add(plotter.NewGrid())
+
+	for i, s := range series {
+		if len(s.Points) == 0 {
+			continue
+		}
+		lineColor := plotutil.Color(i)
+
+		actual, err := newLine(s.Points, lineColor, func(p Point) float64 { return c.units.Temperature(p.Temperature) })
+		if err != nil {
+			return err
+		}
+		feelsLike, err := newLine(s.Points, lineColor, func(p Point) float64 { return c.units.Temperature(p.FeelsLike) })
+		if err != nil {
+			return err
+		}
+		feelsLike.Dashes = []vg.Length{vg.Points(4), vg.Points(3)}
+		temperature.Add(actual, feelsLike)
+		temperature.Legend.Add(s.City, actual)
+		temperature.Legend.Add(c.printer.T(i18n.ChartFeelsLike, s.City), feelsLike)
+
+		humidityLine, err := newLine(s.Points, lineColor, func(p Point) float64 { return p.Humidity })
+		if err != nil {
+			return err
+		}
+		humidity.Add(humidityLine)
+	}
+
+	// у обоих графиков одна шкала времени, чтобы точки совпадали по вертикали
+	humidity.X.Min, humidity.X.Max = temperature.X.Min, temperature.X.Max
+
+	plots := [][]*plot.Plot{{temperature}, {humidity}}
+	tiles := draw.Tiles{Rows: 2, Cols: 1, PadX: vg.Millimeter, PadY: 2 * vg.Millimeter,
+		PadTop: 2 * vg.Millimeter, PadBottom: 2 * vg.Millimeter, PadLeft: 2 * vg.Millimeter, PadRight: 4 * vg.Millimeter}
+	canvases := plot.Align(plots, tiles, draw.New(canvas))
+	for row := range plots {
+		plots[row][0].Draw(canvases[row][0])
+	}
+
+	if _, err := canvas.WriteTo(w); err != nil {
+		return fmt.Errorf("ошибка записи графика: %w", err)
+	}
+	return nil
+}
+
+// newLine линия по точкам ряда; по оси X время в секундах Unix
+func newLine(points []Point, lineColor color.Color, value func(Point) float64) (*plotter.Line, error) {
+	xys := make(plotter.XYs, len(points))
+	for i, p := range points {
+		xys[i].X = float64(p.Time.Unix())
+		xys[i].Y = value(p)
+	}
+
+	line, err := plotter.NewLine(xys)
+	if err != nil {
+		return nil, fmt.Errorf("некорректные данные для графика: %w", err)
+	}
+	line.Color = lineColor
+	line.Width = vg.Points(1.5)
+	return line, nil
+}
+
+func firstPoint(series []Series) (Point, bool) {
+	for _, s := range series {
+		if len(s.Points) > 0 {
+			return s.Points[0], true
+		}
+	}
+	return Point{}, false
+}
@@ -0,0 +1,114 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/history"
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+var start = time.Date(2025, 10, 5, 0, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+func testSeries() []Series {
+	moscow := Series{City: "Moscow"}
+	london := Series{City: "London"}
+	for i := range 24 {
+		at := start.Add(time.Duration(i) * time.Hour)
+		moscow.Points = append(moscow.Points, Point{Time: at, Temperature: float64(i % 8), FeelsLike: float64(i%8) - 3, Humidity: 80})
+		london.Points = append(london.Points, Point{Time: at, Temperature: 12, FeelsLike: 10, Humidity: float64(60 + i)})
+	}
+	return []Series{moscow, london}
+}
+
+func TestSavePNG(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "chart.png")
+	c := New(i18n.NewPrinter(i18n.English), domain.Metric, WithSize(800, 500), WithTitle("Forecast"))
+	require.NoError(t, c.Save(path, testSeries()...))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	config, err := png.DecodeConfig(file)
+	require.NoError(t, err)
+	assert.Equal(t, 800, config.Width)
+	assert.Equal(t, 500, config.Height)
+}
+
+func TestSaveSVG(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "chart.SVG")
+	c := New(i18n.NewPrinter(i18n.Russian), domain.Imperial)
+	require.NoError(t, c.Save(path, testSeries()...))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var svg struct {
+		Width  string `xml:"width,attr"`
+		Height string `xml:"height,attr"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &svg))
+	// размер SVG в пунктах: 1000x700 пикселей при 96 точках на дюйм
+	assert.Equal(t, "750pt", svg.Width)
+	assert.Equal(t, "525pt", svg.Height)
+	assert.Contains(t, string(data), "Moscow")
+}
+
+func TestRenderErrors(t *testing.T) {
+	t.Parallel()
+
+	c := New(i18n.NewPrinter(i18n.English), domain.Metric)
+	var out bytes.Buffer
+
+	assert.ErrorIs(t, c.Render(&out, FormatPNG), ErrNoData)
+	assert.ErrorIs(t, c.Render(&out, FormatPNG, Series{City: "Moscow"}), ErrNoData)
+	assert.Error(t, c.Render(&out, "gif", testSeries()...))
+
+	_, err := FormatFromPath("chart.jpg")
+	assert.Error(t, err)
+	assert.Error(t, c.Save(filepath.Join(t.TempDir(), "chart"), testSeries()...))
+
+	path := filepath.Join(t.TempDir(), "empty.png")
+	assert.ErrorIs(t, c.Save(path, Series{City: "Moscow"}), ErrNoData)
+	assert.NoFileExists(t, path, "failed render leaves no file behind")
+}
+
+func TestFromForecastAndHistory(t *testing.T) {
+	t.Parallel()
+
+	forecast := &domain.Forecast{City: "Moscow", Days: []domain.DailyForecast{
+		{Hourly: []domain.HourlyForecast{{Time: start, Temperature: 5, FeelsLike: 2, Humidity: 80}}},
+		{Hourly: []domain.HourlyForecast{{Time: start.Add(24 * time.Hour), Temperature: 7, Humidity: 70}}},
+	}}
+	series := FromForecast(forecast)
+	assert.Equal(t, "Moscow", series.City)
+	assert.Equal(t, []Point{
+		{Time: start, Temperature: 5, FeelsLike: 2, Humidity: 80},
+		{Time: start.Add(24 * time.Hour), Temperature: 7, Humidity: 70},
+	}, series.Points)
+
+	entries := []history.Entry{
+		{Time: start.Add(time.Hour), Data: domain.WeatherData{City: "Moscow", Temperature: 6}},
+		{Time: start, Data: domain.WeatherData{City: "London", Temperature: 12}},
+		{Time: start, Data: domain.WeatherData{City: "moscow", Temperature: 5}},
+	}
+	all := FromHistory(entries)
+	require.Len(t, all, 2)
+	assert.Equal(t, "London", all[0].City)
+	assert.Equal(t, "moscow", all[1].City)
+	assert.Equal(t, []float64{5, 6}, []float64{all[1].Points[0].Temperature, all[1].Points[1].Temperature})
+}
@@ -0,0 +1,267 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+const (
+	defaultCacheSize = 256
+	defaultMaxStale  = time.Hour
+)
+
+// CacheEntry закешированные данные и время их получения
+type CacheEntry struct {
+	Data      domain.WeatherData `json:"data"`
+	FetchedAt time.Time          `json:"fetched_at"`
+}
+
+// Cache хранилище для CachingProvider
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry) error
+}
+
+// LRUCache кеш в памяти, вытесняющий давно не использованные записи
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = defaultCacheSize
+	}
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruItem).entry, true
+}
+
+func (c *LRUCache) Set(key string, entry CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruItem).key)
+	}
+	return nil
+}
+
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// FileCache кеш на диске: по JSON-файлу на ключ, переживает перезапуск CLI
+type FileCache struct {
+	dir string
+}
+
+// DefaultCacheDir каталог кеша пользователя, например ~/.cache/weather
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("не удалось определить каталог кеша: %w", err)
+	}
+	return filepath.Join(dir, "weather"), nil
+}
+
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ошибка создания каталога кеша: %w", err)
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// path имя файла по хешу ключа: в названиях городов бывают любые символы
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (c *FileCache) Get(key string) (CacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Set пишет во временный файл и переименовывает, чтобы не оставить обрезанную запись
+func (c *FileCache) Set(key string, entry CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации записи кеша: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("ошибка записи кеша: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ошибка записи кеша: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ошибка записи кеша: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("ошибка записи кеша: %w", err)
+	}
+	return nil
+}
+
+// CachingProvider декоратор WeatherProvider с TTL.
+// Если данные устарели, а провайдер недоступен, отдает устаревшие данные не старше maxStale.
+type CachingProvider struct {
+	provider WeatherProvider
+	cache    Cache
+	ttl      time.Duration
+	maxStale time.Duration
+	now      func() time.Time
+	language string
+}
+
+type CacheOption func(*CachingProvider)
+
+func WithCache(cache Cache) CacheOption {
+	return func(c *CachingProvider) {
+		c.cache = cache
+	}
+}
+
+// WithMaxStale сколько после истечения TTL можно отдавать данные при сбое провайдера
+func WithMaxStale(maxStale time.Duration) CacheOption {
+	return func(c *CachingProvider) {
+		c.maxStale = maxStale
+	}
+}
+
+// WithCacheLanguage язык ответов провайдера: он входит в ключ кеша, чтобы после
+// смены --lang не отдавались описания погоды на прежнем языке
+func WithCacheLanguage(language string) CacheOption {
+	return func(c *CachingProvider) {
+		c.language = language
+	}
+}
+
+func WithClock(now func() time.Time) CacheOption {
+	return func(c *CachingProvider) {
+		c.now = now
+	}
+}
+
+func NewCachingProvider(provider WeatherProvider, ttl time.Duration, options ...CacheOption) *CachingProvider {
+	c := &CachingProvider{
+		provider: provider,
+		cache:    NewLRUCache(defaultCacheSize),
+		ttl:      ttl,
+		maxStale: defaultMaxStale,
+		now:      time.Now,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+func (c *CachingProvider) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	key := normalizeCity(city)
+	if c.language != "" {
+		key = c.language + ":" + key
+	}
+	now := c.now()
+
+	entry, cached := c.cache.Get(key)
+	age := now.Sub(entry.FetchedAt)
+	if cached && age < c.ttl {
+		data := entry.Data
+		return &data, nil
+	}
+
+	data, err := c.provider.GetWeather(ctx, city)
+	if err != nil {
+		if cached && age < c.ttl+c.maxStale && ctx.Err() == nil {
+			stale := entry.Data
+			return &stale, nil
+		}
+		return nil, err
+	}
+
+	// ошибка записи в кеш не должна ломать получение погоды
+	c.cache.Set(key, CacheEntry{Data: *data, FetchedAt: now})
+	return data, nil
+}
+
+// RateLimit состояние ограничителя оборачиваемого провайдера
+func (c *CachingProvider) RateLimit() (RateLimitState, bool) {
+	if limited, ok := c.provider.(RateLimited); ok {
+		return limited.RateLimit()
+	}
+	return RateLimitState{}, false
+}
+
+// GetForecast передает запрос прогноза провайдеру без кеширования
+func (c *CachingProvider) GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error) {
+	forecaster, ok := c.provider.(ForecastProvider)
+	if !ok {
+		return nil, fmt.Errorf("провайдер погоды не поддерживает прогноз")
+	}
+	return forecaster.GetForecast(ctx, city, days)
+}
+
+// normalizeCity приводит название к ключу: " New  York" и "new york" - один город
+func normalizeCity(city string) string {
+	return strings.ToLower(strings.Join(strings.Fields(city), " "))
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func TestCachingProviderTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	upstream := newFakeProvider(domain.WeatherData{City: "Moscow", Temperature: 5})
+	provider := NewCachingProvider(upstream, 10*time.Minute, WithClock(clock.Now))
+	ctx := context.Background()
+
+	_, err := provider.GetWeather(ctx, "Moscow")
+	require.NoError(t, err)
+	_, err = provider.GetWeather(ctx, "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 1, upstream.callCount(), "second call is served from cache")
+
+	clock.Advance(11 * time.Minute)
+	_, err = provider.GetWeather(ctx, "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 2, upstream.callCount(), "expired entry is refreshed")
+}
+
+func TestCachingProviderNormalizesCity(t *testing.T) {
+	t.Parallel()
+
+	upstream := newFakeProvider(domain.WeatherData{City: "Москва"})
+	provider := NewCachingProvider(upstream, time.Minute, WithClock(newFakeClock().Now))
+	ctx := context.Background()
+
+	_, err := provider.GetWeather(ctx, "Москва")
+	require.NoError(t, err)
+	_, err = provider.GetWeather(ctx, "  МОСКВА ")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, upstream.callCount())
+	assert.Equal(t, "new york", normalizeCity(" New   York "))
+}
+
+func TestCachingProviderLanguage(t *testing.T) {
+	t.Parallel()
+
+	// общий кеш, как у двух запусков с разным --lang
+	cache := NewLRUCache(10)
+	ru := newFakeProvider(domain.WeatherData{City: "Moscow", Description: "Пасмурно"})
+	en := newFakeProvider(domain.WeatherData{City: "Moscow", Description: "Overcast"})
+	ctx := context.Background()
+
+	data, err := NewCachingProvider(ru, time.Hour, WithCache(cache), WithCacheLanguage("ru")).GetWeather(ctx, "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "Пасмурно", data.Description)
+
+	data, err = NewCachingProvider(en, time.Hour, WithCache(cache), WithCacheLanguage("en")).GetWeather(ctx, "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "Overcast", data.Description, "other language is not served from cache")
+	assert.Equal(t, 1, en.callCount())
+
+	data, err = NewCachingProvider(ru, time.Hour, WithCache(cache), WithCacheLanguage("ru")).GetWeather(ctx, "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "Пасмурно", data.Description)
+	assert.Equal(t, 1, ru.callCount(), "same language still hits the cache")
+}
+
+func TestCachingProviderServesStaleOnFailure(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	upstream := newFakeProvider(domain.WeatherData{City: "Moscow", Temperature: 5})
+	provider := NewCachingProvider(upstream, 10*time.Minute, WithClock(clock.Now), WithMaxStale(time.Hour))
+	ctx := context.Background()
+
+	_, err := provider.GetWeather(ctx, "Moscow")
+	require.NoError(t, err)
+
+	upstream.setErr(errUpstream)
+	clock.Advance(30 * time.Minute)
+
+	data, err := provider.GetWeather(ctx, "Moscow")
+	require.NoError(t, err, "stale data is returned while upstream fails")
+	assert.Equal(t, 5.0, data.Temperature)
+	assert.Equal(t, 2, upstream.callCount(), "upstream is still asked first")
+
+	clock.Advance(time.Hour)
+	_, err = provider.GetWeather(ctx, "Moscow")
+	assert.ErrorIs(t, err, errUpstream, "too old data is not served")
+}
+
+func TestCachingProviderForecastPassthrough(t *testing.T) {
+	t.Parallel()
+
+	provider := NewCachingProvider(newFakeProvider(), time.Minute)
+	_, err := provider.GetForecast(context.Background(), "Moscow", 1)
+	assert.Error(t, err, "fake provider has no forecast")
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	cache := NewLRUCache(2)
+	cache.Set("a", CacheEntry{Data: domain.WeatherData{City: "a"}})
+	cache.Set("b", CacheEntry{Data: domain.WeatherData{City: "b"}})
+	cache.Get("a")
+	cache.Set("c", CacheEntry{Data: domain.WeatherData{City: "c"}})
+
+	_, ok := cache.Get("b")
+	assert.False(t, ok, "least recently used entry is evicted")
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestFileCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir)
+	require.NoError(t, err)
+
+	fetched := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := CacheEntry{Data: domain.WeatherData{City: "Лондон", Temperature: 11.5}, FetchedAt: fetched}
+	require.NoError(t, cache.Set("лондон", entry))
+
+	reopened, err := NewFileCache(dir)
+	require.NoError(t, err)
+
+	got, ok := reopened.Get("лондон")
+	require.True(t, ok)
+	assert.Equal(t, entry.Data, got.Data)
+	assert.True(t, fetched.Equal(got.FetchedAt))
+
+	_, ok = reopened.Get("paris")
+	assert.False(t, ok)
+}
+
+func TestCachingProviderWithFileCache(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewFileCache(t.TempDir())
+	require.NoError(t, err)
+
+	clock := newFakeClock()
+	upstream := newFakeProvider(domain.WeatherData{City: "Paris"})
+	for i := 0; i < 3; i++ {
+		provider := NewCachingProvider(upstream, time.Minute, WithCache(cache), WithClock(clock.Now))
+		_, err := provider.GetWeather(context.Background(), "Paris")
+		require.NoError(t, err, fmt.Sprintf("run %d", i))
+	}
+
+	assert.Equal(t, 1, upstream.callCount(), "disk cache survives new provider instances")
+}
@@ -1,108 +1,256 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"example/src/seminar3/tasks/weather/domain"
 )
 
 const (
-	wttrInUrl  = "https://wttr.in/%s?format=j1"
-	maxRetries = 3
-	retryDelay = 2 * time.Second
+	// DefaultWttrInURL адрес wttr.in по умолчанию
+	DefaultWttrInURL = "https://wttr.in"
+	// DefaultUserAgent представляемся сервисам погоды, чтобы быть хорошим гражданином интернета
+	DefaultUserAgent = "WeatherCLI/1.0 (educational project)"
+	// DefaultTimeout срок одного HTTP-запроса к сервису погоды
+	DefaultTimeout = 10 * time.Second
+
+	wttrInPath      = "/%s?format=j1"
+	maxForecastDays = 3 // wttr.in отдает прогноз не больше чем на 3 дня
 )
 
 // WttrInProvider реализация для wttr.in
 type WttrInProvider struct {
-	client  *http.Client
-	baseURL string
+	client      *http.Client
+	baseURL     string
+	userAgent   string
+	retryPolicy RetryPolicy
+	sleep       Sleeper
+	random      func() float64
+	observer    Observer
+	limiter     *RateLimiter
+	validators  *validators
+}
+
+func NewWttrInProvider(options ...Option) *WttrInProvider {
+	w := &WttrInProvider{
+		client:      &http.Client{Timeout: DefaultTimeout},
+		baseURL:     DefaultWttrInURL + wttrInPath,
+		userAgent:   DefaultUserAgent,
+		retryPolicy: DefaultRetryPolicy(),
+		sleep:       sleepContext,
+		random:      defaultRandom,
+		observer:    nopObserver{},
+		limiter:     defaultLimiter(),
+		validators:  newValidators(),
+	}
+
+	for _, option := range options {
+		option(w)
+	}
+
+	return w
+}
+
+// WithBaseURL адрес сервиса вместо https://wttr.in, например зеркала или тестового сервера
+func WithBaseURL(baseURL string) Option {
+	return func(w *WttrInProvider) {
+		if baseURL != "" {
+			w.baseURL = strings.TrimRight(baseURL, "/") + wttrInPath
+		}
+	}
+}
+
+// WithTimeout срок одной попытки запроса; 0 - без ограничения
+func WithTimeout(timeout time.Duration) Option {
+	return func(w *WttrInProvider) {
+		w.client.Timeout = timeout
+	}
+}
+
+// WithUserAgent значение заголовка User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(w *WttrInProvider) {
+		if userAgent != "" {
+			w.userAgent = userAgent
+		}
+	}
 }
 
-func NewWttrInProvider() *WttrInProvider {
-	return &WttrInProvider{
-		client:  &http.Client{Timeout: 10 * time.Second},
-		baseURL: wttrInUrl,
+// WithRateLimiter общий для нескольких провайдеров ограничитель частоты запросов;
+// nil отключает ограничение. По умолчанию все провайдеры делят один ограничитель
+// на DefaultRateBurst запросов подряд и далее раз в DefaultRateInterval.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(w *WttrInProvider) {
+		w.limiter = limiter
+	}
+}
+
+// RateLimit состояние ограничителя частоты запросов; false, если он отключен
+func (w *WttrInProvider) RateLimit() (RateLimitState, bool) {
+	if w.limiter == nil {
+		return RateLimitState{}, false
+	}
+	return w.limiter.State(), true
+}
+
+// WithTransport транспорт HTTP-клиента, например cassette.Transport для тестов без сети;
+// nil - http.DefaultTransport
+func WithTransport(transport http.RoundTripper) Option {
+	return func(w *WttrInProvider) {
+		w.client.Transport = transport
 	}
 }
 
 // GetWeather получает данные о погоде с retry логикой
-func (w *WttrInProvider) GetWeather(city string) (*domain.WeatherData, error) {
-	if city == "" {
-		return nil, fmt.Errorf("город не может быть пустым")
+func (w *WttrInProvider) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	response, err := w.fetch(ctx, city)
+	if err != nil {
+		return nil, err
 	}
+	return w.transformResponse(response, city)
+}
 
-	url := fmt.Sprintf(w.baseURL, city)
+// GetForecast получает прогноз на days дней (от 1 до 3) с почасовой разбивкой
+func (w *WttrInProvider) GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error) {
+	if days < 1 || days > maxForecastDays {
+		return nil, fmt.Errorf("количество дней прогноза должно быть от 1 до %d, получено %d", maxForecastDays, days)
+	}
 
-	var lastError error
-	var weatherData *domain.WeatherData
+	response, err := w.fetch(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+	return w.transformForecast(response, city, days)
+}
 
-	// Retry логика
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			fmt.Printf("Повторная попытка %d/%d через %v...\n", attempt, maxRetries, retryDelay)
-			time.Sleep(retryDelay)
+// fetch запрашивает и разбирает ответ wttr.in с retry логикой.
+// city - строка местоположения в формате domain.ParseLocation.
+func (w *WttrInProvider) fetch(ctx context.Context, city string) (*domain.WttrInResponse, error) {
+	location, err := domain.ParseLocation(city)
+	if err != nil {
+		return nil, err
+	}
+
+	requestURL := fmt.Sprintf(w.baseURL, wttrPath(location))
+	policy := w.retryPolicy
+
+	for attempt := 1; ; attempt++ {
+		event := AttemptEvent{City: city, URL: requestURL, Attempt: attempt, MaxAttempts: policy.MaxAttempts}
+
+		if w.limiter != nil {
+			if err := w.limiter.Wait(ctx); err != nil {
+				event.Err = err
+				w.observer.OnFailure(event)
+				return nil, err
+			}
 		}
+		w.observer.OnAttempt(event)
 
-		body, err := w.makeRequest(url)
-		if err != nil {
-			lastError = err
-			fmt.Printf("Попытка %d неудачна: %v\n", attempt+1, err)
-			continue
+		start := time.Now()
+		response, err := w.attempt(ctx, requestURL)
+		event.Duration = time.Since(start)
+		event.Err = err
+
+		if err == nil {
+			w.observer.OnSuccess(event)
+			return response, nil
 		}
 
-		weatherData, err = w.parseResponse(body, city)
-		if err != nil {
-			lastError = err
-			fmt.Printf("Попытка %d: ошибка парсинга: %v\n", attempt+1, err)
-			continue
+		// fail сообщает наблюдателю итоговую ошибку, которую получит вызывающий
+		fail := func(err error) error {
+			event.Err = err
+			w.observer.OnFailure(event)
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return nil, fail(fmt.Errorf("запрос прерван: %w", ctx.Err()))
+		}
+		// сервер просит подождать всех, кто ходит через этот провайдер, а не только нас
+		if retryAfter := retryAfterOf(err); retryAfter > 0 && w.limiter != nil {
+			w.limiter.Pause(retryAfter)
+		}
+		if !isRetryable(err) {
+			return nil, fail(err)
+		}
+		if attempt >= policy.MaxAttempts {
+			return nil, fail(fmt.Errorf("не удалось получить данные после %d попыток: %w", attempt, err))
+		}
+
+		delay, ok := policy.delay(attempt, retryAfterOf(err), w.random)
+		if !ok {
+			return nil, fail(fmt.Errorf("сервер просит повторить запрос позже чем через %v: %w", retryAfterOf(err), err))
+		}
+
+		w.observer.OnRetry(event, delay)
+		if err := w.sleep(ctx, delay); err != nil {
+			return nil, fail(fmt.Errorf("запрос прерван: %w", err))
 		}
-		fmt.Printf("Данные успешно получены (попытка %d)\n", attempt+1)
-		return weatherData, nil
 	}
+}
 
-	return nil, fmt.Errorf("не удалось получить данные после %d попыток: %w", maxRetries+1, lastError)
+// attempt выполняет одну попытку: запрос и разбор ответа
+func (w *WttrInProvider) attempt(ctx context.Context, requestURL string) (*domain.WttrInResponse, error) {
+	body, err := w.makeRequest(ctx, requestURL)
+	if err != nil {
+		return nil, err
+	}
+	return w.parseResponse(body)
 }
 
 // makeRequest выполняет HTTP запрос с обработкой ошибок
-func (w *WttrInProvider) makeRequest(url string) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (w *WttrInProvider) makeRequest(ctx context.Context, requestURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
 	}
 
-	// Добавляем User-Agent чтобы быть хорошим гражданином интернета
-	req.Header.Set("User-Agent", "WeatherCLI/1.0 (educational project)")
+	req.Header.Set("User-Agent", w.userAgent)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w.validators.apply(req, requestURL)
 
 	resp, err := w.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
+		return nil, requestError(ctx, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		if body, ok := w.validators.get(requestURL); ok {
+			return body, nil
+		}
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("сервер вернул ошибку: %s", resp.Status)
+		return nil, &statusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения ответа: %w", err)
+		return nil, err
 	}
+	w.validators.set(requestURL, resp.Header, body)
 
 	return body, nil
 }
 
-// parseResponse парсит JSON ответ и преобразует в доменную модель
-func (w *WttrInProvider) parseResponse(body []byte, requestedCity string) (*domain.WeatherData, error) {
+// parseResponse парсит JSON ответ
+func (w *WttrInProvider) parseResponse(body []byte) (*domain.WttrInResponse, error) {
 	var wttrResponse domain.WttrInResponse
 	if err := json.Unmarshal(body, &wttrResponse); err != nil {
-		return nil, fmt.Errorf("ошибка парсинга JSON: %w", err)
+		return nil, newParseError("body", string(body), err)
 	}
 
-	return w.transformResponse(&wttrResponse, requestedCity)
+	return &wttrResponse, nil
 }
 
 // transformResponse преобразует сырые данные API в нашу доменную модель
@@ -110,40 +258,281 @@ func (w *WttrInProvider) transformResponse(
 	response *domain.WttrInResponse,
 	requestedCity string,
 ) (*domain.WeatherData, error) {
+	if err := validateCurrent(response); err != nil {
+		return nil, err
+	}
 	condition := response.CurrentCondition[0]
 
 	temp, err := parseFloat(condition.TempC)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга температуры: %w", err)
+		return nil, newParseError("current_condition[0].temp_C", condition.TempC, err)
 	}
 
 	humidity, err := parseInt(condition.Humidity)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга влажности: %w", err)
+		return nil, newParseError("current_condition[0].humidity", condition.Humidity, err)
 	}
 
 	windSpeed, err := parseFloat(condition.WindSpeedKmph)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга скорости ветра: %w", err)
+		return nil, newParseError("current_condition[0].windspeedKmph", condition.WindSpeedKmph, err)
 	}
 
 	feelsLike, err := parseFloat(condition.FeelsLikeC)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга ощущаемой температуры: %w", err)
+		return nil, newParseError("current_condition[0].FeelsLikeC", condition.FeelsLikeC, err)
 	}
 
 	cityName := w.getCityName(response.NearestArea, requestedCity)
 
-	return &domain.WeatherData{
-		City:        cityName,
-		Temperature: temp,
-		Humidity:    humidity,
-		Description: condition.WeatherDesc[0].Value,
-		WindSpeed:   windSpeed,
-		FeelsLike:   feelsLike,
+	data := &domain.WeatherData{
+		City:          cityName,
+		Temperature:   temp,
+		Humidity:      humidity,
+		Description:   condition.WeatherDesc[0].Value,
+		WindSpeed:     windSpeed,
+		FeelsLike:     feelsLike,
+		WindDirection: condition.WindDir16Point,
+	}
+	if err := w.transformExtended(condition, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// transformExtended разбирает необязательные поля: если поля нет, значение остается nil
+func (w *WttrInProvider) transformExtended(condition domain.CurrentCondition, data *domain.WeatherData) error {
+	floats := []struct {
+		field  string
+		raw    string
+		target **float64
+	}{
+		{"pressure", condition.Pressure, &data.Pressure},
+		{"visibility", condition.Visibility, &data.Visibility},
+		{"precipMM", condition.PrecipMM, &data.Precipitation},
+	}
+	for _, f := range floats {
+		if f.raw == "" {
+			continue
+		}
+		value, err := parseFloat(f.raw)
+		if err != nil {
+			return newParseError("current_condition[0]."+f.field, f.raw, err)
+		}
+		*f.target = domain.Ptr(value)
+	}
+
+	ints := []struct {
+		field  string
+		raw    string
+		target **int
+	}{
+		{"uvIndex", condition.UVIndex, &data.UVIndex},
+		{"cloudcover", condition.CloudCover, &data.CloudCover},
+	}
+	for _, f := range ints {
+		if f.raw == "" {
+			continue
+		}
+		value, err := parseInt(f.raw)
+		if err != nil {
+			return newParseError("current_condition[0]."+f.field, f.raw, err)
+		}
+		*f.target = domain.Ptr(value)
+	}
+
+	if condition.WindDirDegree != "" {
+		degree, err := parseInt(condition.WindDirDegree)
+		if err != nil {
+			return newParseError("current_condition[0].winddirDegree", condition.WindDirDegree, err)
+		}
+		data.WindDegree = domain.Ptr(degree)
+	}
+
+	if condition.LocalObsDateTime != "" {
+		observedAt, err := parseObservation(condition.LocalObsDateTime, condition.ObservationTime)
+		if err != nil {
+			return newParseError("current_condition[0].localObsDateTime", condition.LocalObsDateTime, err)
+		}
+		data.ObservedAt = observedAt
+	}
+	return nil
+}
+
+// parseObservation разбирает местное время наблюдения "2025-10-05 12:05 PM".
+// Часовой пояс wttr.in не сообщает, поэтому он вычисляется по разнице
+// с временем наблюдения в UTC ("09:05 AM"); без него время считается UTC.
+func parseObservation(local, utcClock string) (time.Time, error) {
+	observed, err := time.Parse("2006-01-02 03:04 PM", local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if utcClock == "" {
+		return observed, nil
+	}
+
+	clock, err := time.Parse("03:04 PM", utcClock)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	localMinutes := observed.Hour()*60 + observed.Minute()
+	utcMinutes := clock.Hour()*60 + clock.Minute()
+	offset := localMinutes - utcMinutes
+	// смещение поясов лежит в пределах от -12 до +14 часов
+	switch {
+	case offset > 14*60:
+		offset -= 24 * 60
+	case offset < -12*60:
+		offset += 24 * 60
+	}
+
+	zone := time.FixedZone(fmt.Sprintf("UTC%+03d:%02d", offset/60, abs(offset%60)), offset*60)
+	return time.Date(observed.Year(), observed.Month(), observed.Day(),
+		observed.Hour(), observed.Minute(), 0, 0, zone), nil
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// transformForecast преобразует блок weather ответа в прогноз на days дней
+func (w *WttrInProvider) transformForecast(
+	response *domain.WttrInResponse,
+	requestedCity string,
+	days int,
+) (*domain.Forecast, error) {
+	if err := validateForecast(response, days); err != nil {
+		return nil, err
+	}
+
+	forecast := &domain.Forecast{
+		City: w.getCityName(response.NearestArea, requestedCity),
+		Days: make([]domain.DailyForecast, 0, days),
+	}
+
+	for i, day := range response.Weather[:days] {
+		daily, err := w.transformDay(fmt.Sprintf("weather[%d]", i), day)
+		if err != nil {
+			return nil, err
+		}
+		forecast.Days = append(forecast.Days, daily)
+	}
+
+	return forecast, nil
+}
+
+// transformDay преобразует день прогноза; path - путь к дню в ответе для ParseError
+func (w *WttrInProvider) transformDay(path string, day domain.WeatherDay) (domain.DailyForecast, error) {
+	date, err := time.Parse("2006-01-02", day.Date)
+	if err != nil {
+		return domain.DailyForecast{}, newParseError(path+".date", day.Date, err)
+	}
+
+	minTemp, err := parseFloat(day.MinTempC)
+	if err != nil {
+		return domain.DailyForecast{}, newParseError(path+".mintempC", day.MinTempC, err)
+	}
+
+	maxTemp, err := parseFloat(day.MaxTempC)
+	if err != nil {
+		return domain.DailyForecast{}, newParseError(path+".maxtempC", day.MaxTempC, err)
+	}
+
+	avgTemp, err := parseFloat(day.AvgTempC)
+	if err != nil {
+		return domain.DailyForecast{}, newParseError(path+".avgtempC", day.AvgTempC, err)
+	}
+
+	daily := domain.DailyForecast{
+		Date:    date,
+		MinTemp: minTemp,
+		MaxTemp: maxTemp,
+		AvgTemp: avgTemp,
+		Hourly:  make([]domain.HourlyForecast, 0, len(day.Hourly)),
+	}
+
+	if len(day.Astronomy) > 0 {
+		daily.Sunrise = day.Astronomy[0].Sunrise
+		daily.Sunset = day.Astronomy[0].Sunset
+	}
+
+	for j, hour := range day.Hourly {
+		hourly, err := w.transformHour(fmt.Sprintf("%s.hourly[%d]", path, j), date, hour)
+		if err != nil {
+			return domain.DailyForecast{}, err
+		}
+		daily.Hourly = append(daily.Hourly, hourly)
+	}
+
+	return daily, nil
+}
+
+func (w *WttrInProvider) transformHour(path string, date time.Time, hour domain.HourlyData) (domain.HourlyForecast, error) {
+	// время приходит в виде "0", "300", ..., "2100"
+	hhmm, err := parseInt(hour.Time)
+	if err != nil {
+		return domain.HourlyForecast{}, newParseError(path+".time", hour.Time, err)
+	}
+
+	temp, err := parseFloat(hour.TempC)
+	if err != nil {
+		return domain.HourlyForecast{}, newParseError(path+".tempC", hour.TempC, err)
+	}
+
+	feelsLike, err := parseFloat(hour.FeelsLikeC)
+	if err != nil {
+		return domain.HourlyForecast{}, newParseError(path+".FeelsLikeC", hour.FeelsLikeC, err)
+	}
+
+	humidity, err := parseInt(hour.Humidity)
+	if err != nil {
+		return domain.HourlyForecast{}, newParseError(path+".humidity", hour.Humidity, err)
+	}
+
+	windSpeed, err := parseFloat(hour.WindSpeedKmph)
+	if err != nil {
+		return domain.HourlyForecast{}, newParseError(path+".windspeedKmph", hour.WindSpeedKmph, err)
+	}
+
+	// вероятность дождя встречается не во всех ответах
+	chanceOfRain, _ := parseInt(hour.ChanceOfRain)
+
+	var description string
+	if len(hour.WeatherDesc) > 0 {
+		description = hour.WeatherDesc[0].Value
+	}
+
+	return domain.HourlyForecast{
+		Time:         date.Add(time.Duration(hhmm/100)*time.Hour + time.Duration(hhmm%100)*time.Minute),
+		Temperature:  temp,
+		FeelsLike:    feelsLike,
+		Humidity:     humidity,
+		WindSpeed:    windSpeed,
+		ChanceOfRain: chanceOfRain,
+		Description:  description,
 	}, nil
 }
 
+// wttrPath кодирует местоположение в путь запроса wttr.in
+func wttrPath(location domain.Location) string {
+	switch location.Kind {
+	case domain.LocationAuto:
+		// без пути wttr.in определяет место по IP-адресу запроса
+		return ""
+	case domain.LocationAirport:
+		return strings.ToLower(location.Code)
+	case domain.LocationCoordinates:
+		return location.String()
+	default:
+		// пробелы и не-ASCII символы ("New York", "Москва") экранируются
+		return url.PathEscape(location.Name)
+	}
+}
+
 // getCityName извлекает название города из ответа
 func (w *WttrInProvider) getCityName(nearestAreas []domain.NearestArea, requestedCity string) string {
 	if len(nearestAreas) > 0 && len(nearestAreas[0].AreaName) > 0 {
@@ -152,6 +541,19 @@ func (w *WttrInProvider) getCityName(nearestAreas []domain.NearestArea, requeste
 	return requestedCity
 }
 
+// sleepContext ждет d или отмены контекста
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // Вспомогательные функции для парсинга
 func parseFloat(s string) (float64, error) {
 	var f float64
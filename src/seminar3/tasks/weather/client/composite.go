@@ -0,0 +1,463 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+const (
+	defaultBreakerThreshold = 3
+	defaultBreakerCooldown  = 30 * time.Second
+	defaultAggregateTimeout = 10 * time.Second
+)
+
+// NamedProvider провайдер с именем; имя попадает в WeatherData.Sources
+type NamedProvider struct {
+	Name     string
+	Provider WeatherProvider
+}
+
+// BreakerState состояние предохранителя
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // запросы идут
+	BreakerOpen                         // провайдер отключен до конца паузы
+	BreakerHalfOpen                     // пропускаем один пробный запрос
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker отключает провайдер после threshold ошибок подряд на время cooldown
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Allow сообщает, можно ли сейчас обращаться к провайдеру
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// release отпускает пробный запрос, не меняя состояние (например, при отмене контекста)
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// FailoverProvider опрашивает провайдеры по приоритету до первого успешного ответа.
+// Провайдер, который раз за разом падает, временно пропускается предохранителем.
+type FailoverProvider struct {
+	providers []NamedProvider
+	breakers  []*CircuitBreaker
+}
+
+type FailoverOption func(*FailoverProvider)
+
+// WithBreaker задает порог ошибок и паузу предохранителей
+func WithBreaker(threshold int, cooldown time.Duration) FailoverOption {
+	return func(f *FailoverProvider) {
+		for i := range f.breakers {
+			f.breakers[i].threshold = max(threshold, 1)
+			f.breakers[i].cooldown = cooldown
+		}
+	}
+}
+
+// WithBreakerClock подменяет часы предохранителей в тестах
+func WithBreakerClock(now func() time.Time) FailoverOption {
+	return func(f *FailoverProvider) {
+		for _, breaker := range f.breakers {
+			breaker.now = now
+		}
+	}
+}
+
+func NewFailoverProvider(providers []NamedProvider, options ...FailoverOption) *FailoverProvider {
+	f := &FailoverProvider{
+		providers: providers,
+		breakers:  make([]*CircuitBreaker, len(providers)),
+	}
+	for i := range providers {
+		f.breakers[i] = NewCircuitBreaker(defaultBreakerThreshold, defaultBreakerCooldown)
+	}
+
+	for _, option := range options {
+		option(f)
+	}
+
+	return f
+}
+
+func (f *FailoverProvider) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	var errs []error
+
+	for i, named := range f.providers {
+		breaker := f.breakers[i]
+		if !breaker.Allow() {
+			errs = append(errs, fmt.Errorf("%s: временно отключен после серии ошибок", named.Name))
+			continue
+		}
+
+		data, err := named.Provider.GetWeather(ctx, city)
+		if err == nil {
+			breaker.Success()
+			data.Sources = []string{named.Name}
+			return data, nil
+		}
+
+		if ctx.Err() != nil {
+			breaker.release()
+			return nil, fmt.Errorf("запрос прерван: %w", ctx.Err())
+		}
+
+		if isBreakerFailure(err) {
+			breaker.Failure()
+		} else {
+			breaker.release()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", named.Name, err))
+	}
+
+	return nil, fmt.Errorf("все провайдеры недоступны: %w", errors.Join(errs...))
+}
+
+// GetForecast берет прогноз у первого провайдера, который его поддерживает и отвечает
+func (f *FailoverProvider) GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error) {
+	var errs []error
+
+	for i, named := range f.providers {
+		forecaster, ok := named.Provider.(ForecastProvider)
+		if !ok {
+			continue
+		}
+		if !f.breakers[i].Allow() {
+			errs = append(errs, fmt.Errorf("%s: временно отключен после серии ошибок", named.Name))
+			continue
+		}
+
+		forecast, err := forecaster.GetForecast(ctx, city, days)
+		if err == nil {
+			f.breakers[i].Success()
+			return forecast, nil
+		}
+
+		if ctx.Err() != nil {
+			f.breakers[i].release()
+			return nil, fmt.Errorf("запрос прерван: %w", ctx.Err())
+		}
+		if isBreakerFailure(err) {
+			f.breakers[i].Failure()
+		} else {
+			f.breakers[i].release()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", named.Name, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("ни один провайдер не поддерживает прогноз")
+	}
+	return nil, fmt.Errorf("все провайдеры недоступны: %w", errors.Join(errs...))
+}
+
+// isBreakerFailure говорит ли ошибка о сбое самого провайдера. Ошибки в запросе
+// (неизвестный город, неподдерживаемое место) и превышение лимита запросов
+// нейтральны, а непонятный ответ, как и сетевые сбои и 5xx, считается сбоем.
+func isBreakerFailure(err error) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return false
+	}
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return true
+	}
+	return isRetryable(err)
+}
+
+// BreakerStates состояния предохранителей по именам провайдеров
+func (f *FailoverProvider) BreakerStates() map[string]BreakerState {
+	states := make(map[string]BreakerState, len(f.providers))
+	for i, named := range f.providers {
+		states[named.Name] = f.breakers[i].State()
+	}
+	return states
+}
+
+// AggregateProvider опрашивает все провайдеры параллельно и объединяет ответы:
+// медиана числовых значений и самое частое описание.
+type AggregateProvider struct {
+	providers  []NamedProvider
+	timeout    time.Duration // общий срок ожидания ответов
+	minSources int
+}
+
+type AggregateOption func(*AggregateProvider)
+
+// WithProviderTimeout ограничивает время ожидания провайдеров; не успевшие не учитываются.
+// 0 и отрицательное значение снимают ограничение, остается только срок контекста вызова.
+func WithProviderTimeout(timeout time.Duration) AggregateOption {
+	return func(a *AggregateProvider) {
+		a.timeout = timeout
+	}
+}
+
+// WithMinSources минимальное число успешных ответов, иначе ошибка
+func WithMinSources(n int) AggregateOption {
+	return func(a *AggregateProvider) {
+		a.minSources = max(n, 1)
+	}
+}
+
+func NewAggregateProvider(providers []NamedProvider, options ...AggregateOption) *AggregateProvider {
+	a := &AggregateProvider{
+		providers:  providers,
+		timeout:    defaultAggregateTimeout,
+		minSources: 1,
+	}
+
+	for _, option := range options {
+		option(a)
+	}
+
+	return a
+}
+
+type providerResult struct {
+	index int
+	data  *domain.WeatherData
+	err   error
+}
+
+func (a *AggregateProvider) GetWeather(parent context.Context, city string) (*domain.WeatherData, error) {
+	ctx := parent
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, a.timeout)
+		defer cancel()
+	}
+
+	// буфер на всех, чтобы опоздавшие горутины не блокировались после выхода
+	resultsCh := make(chan providerResult, len(a.providers))
+	for i, named := range a.providers {
+		go func() {
+			data, err := named.Provider.GetWeather(ctx, city)
+			resultsCh <- providerResult{index: i, data: data, err: err}
+		}()
+	}
+
+	results := make([]*providerResult, len(a.providers))
+	for received := 0; received < len(a.providers); received++ {
+		select {
+		case result := <-resultsCh:
+			results[result.index] = &result
+		case <-ctx.Done():
+			received = len(a.providers) // провайдеры, не успевшие ответить, считаются ошибкой
+		}
+	}
+
+	if parent.Err() != nil {
+		return nil, fmt.Errorf("запрос прерван: %w", parent.Err())
+	}
+
+	var collected []*domain.WeatherData
+	var sources []string
+	var errs []error
+	for i, result := range results {
+		name := a.providers[i].Name
+		switch {
+		case result == nil:
+			errs = append(errs, fmt.Errorf("%s: нет ответа за %v", name, a.timeout))
+		case result.err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", name, result.err))
+		default:
+			collected = append(collected, result.data)
+			sources = append(sources, name)
+		}
+	}
+
+	if len(collected) < a.minSources {
+		return nil, fmt.Errorf("ответили %d из %d провайдеров, нужно минимум %d: %w",
+			len(collected), len(a.providers), a.minSources, errors.Join(errs...))
+	}
+
+	merged := mergeWeather(collected)
+	merged.Sources = sources
+	return merged, nil
+}
+
+// mergeWeather объединяет ответы; порядок data задает приоритет при равенстве голосов
+func mergeWeather(data []*domain.WeatherData) *domain.WeatherData {
+	temps := make([]float64, len(data))
+	feels := make([]float64, len(data))
+	winds := make([]float64, len(data))
+	humidity := make([]float64, len(data))
+	descriptions := make([]string, len(data))
+
+	for i, d := range data {
+		temps[i] = d.Temperature
+		feels[i] = d.FeelsLike
+		winds[i] = d.WindSpeed
+		humidity[i] = float64(d.Humidity)
+		descriptions[i] = d.Description
+	}
+
+	merged := &domain.WeatherData{
+		City:          data[0].City,
+		Temperature:   median(temps),
+		FeelsLike:     median(feels),
+		WindSpeed:     median(winds),
+		Humidity:      int(math.Round(median(humidity))),
+		Description:   majority(descriptions),
+		Pressure:      medianPresent(data, func(d *domain.WeatherData) *float64 { return d.Pressure }),
+		Visibility:    medianPresent(data, func(d *domain.WeatherData) *float64 { return d.Visibility }),
+		Precipitation: medianPresent(data, func(d *domain.WeatherData) *float64 { return d.Precipitation }),
+		UVIndex:       medianPresent(data, func(d *domain.WeatherData) *int { return d.UVIndex }),
+		CloudCover:    medianPresent(data, func(d *domain.WeatherData) *int { return d.CloudCover }),
+	}
+
+	// направление ветра и время наблюдения берем у самого приоритетного источника, где они есть
+	for _, d := range data {
+		if merged.WindDirection == "" && merged.WindDegree == nil && (d.WindDirection != "" || d.WindDegree != nil) {
+			merged.WindDirection = d.WindDirection
+			merged.WindDegree = d.WindDegree
+		}
+		if merged.ObservedAt.IsZero() {
+			merged.ObservedAt = d.ObservedAt
+		}
+	}
+
+	return merged
+}
+
+// medianPresent медиана расширенного поля по источникам, где оно есть (не nil);
+// целые поля округляются. Если поля нет ни у кого, результат nil.
+func medianPresent[T int | float64](data []*domain.WeatherData, field func(*domain.WeatherData) *T) *T {
+	var values []float64
+	for _, d := range data {
+		if v := field(d); v != nil {
+			values = append(values, float64(*v))
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	m := median(values)
+	var zero T
+	if _, ok := any(zero).(int); ok {
+		m = math.Round(m)
+	}
+	return domain.Ptr(T(m))
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// majority самое частое значение без учета регистра и лишних пробелов, чтобы
+// "Overcast" и "overcast " считались одним голосом. Возвращается написание
+// первого источника с этим значением; при равенстве голосов побеждает встреченное раньше.
+func majority(values []string) string {
+	counts := make(map[string]int, len(values))
+	for _, v := range values {
+		counts[normalizeDescription(v)]++
+	}
+
+	best, bestCount := "", 0
+	for _, v := range values {
+		if count := counts[normalizeDescription(v)]; count > bestCount {
+			best, bestCount = v, count
+		}
+	}
+	return best
+}
+
+func normalizeDescription(description string) string {
+	return strings.ToLower(strings.Join(strings.Fields(description), " "))
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+func TestFailoverProviderPriority(t *testing.T) {
+	t.Parallel()
+
+	primary := newFakeProvider(domain.WeatherData{City: "Moscow", Temperature: 1})
+	secondary := newFakeProvider(domain.WeatherData{City: "Moscow", Temperature: 2})
+	provider := NewFailoverProvider([]NamedProvider{
+		{Name: "primary", Provider: primary},
+		{Name: "secondary", Provider: secondary},
+	})
+
+	data, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, data.Temperature)
+	assert.Equal(t, []string{"primary"}, data.Sources)
+	assert.Zero(t, secondary.callCount())
+
+	primary.setErr(errUpstream)
+	data, err = provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, data.Temperature)
+	assert.Equal(t, []string{"secondary"}, data.Sources)
+}
+
+func TestFailoverProviderCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	primary := newFakeProvider(domain.WeatherData{City: "Moscow", Temperature: 1})
+	primary.setErr(errUpstream)
+	secondary := newFakeProvider(domain.WeatherData{City: "Moscow", Temperature: 2})
+
+	provider := NewFailoverProvider([]NamedProvider{
+		{Name: "primary", Provider: primary},
+		{Name: "secondary", Provider: secondary},
+	}, WithBreaker(2, time.Minute), WithBreakerClock(clock.Now))
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		_, err := provider.GetWeather(ctx, "Moscow")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, primary.callCount(), "breaker opens after 2 failures")
+	assert.Equal(t, BreakerOpen, provider.BreakerStates()["primary"])
+
+	clock.Advance(2 * time.Minute)
+	primary.setErr(nil)
+
+	data, err := provider.GetWeather(ctx, "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"primary"}, data.Sources, "half-open probe succeeds")
+	assert.Equal(t, BreakerClosed, provider.BreakerStates()["primary"])
+}
+
+func TestFailoverProviderBreakerClassification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected BreakerState
+	}{
+		{"city not found", &statusError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}, BreakerClosed},
+		{"local rate limit", fmt.Errorf("%w: следующий запрос возможен через 1s", ErrRateLimited), BreakerClosed},
+		{"upstream 429", &statusError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}, BreakerClosed},
+		{"malformed response", &ParseError{Field: "temp_C", Raw: "abc", Err: errors.New("invalid syntax")}, BreakerOpen},
+		{"upstream 503", &statusError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}, BreakerOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := NewFailoverProvider([]NamedProvider{{Name: "wttrin", Provider: failingProvider(tt.err)}}, WithBreaker(1, time.Minute))
+			_, err := provider.GetWeather(context.Background(), "Moscow")
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, provider.BreakerStates()["wttrin"])
+		})
+	}
+}
+
+func TestFailoverProviderAllFail(t *testing.T) {
+	t.Parallel()
+
+	provider := NewFailoverProvider([]NamedProvider{
+		{Name: "a", Provider: failingProvider(errUpstream)},
+		{Name: "b", Provider: failingProvider(errUpstream)},
+	})
+
+	_, err := provider.GetWeather(context.Background(), "Moscow")
+	assert.ErrorIs(t, err, errUpstream)
+	assert.ErrorContains(t, err, "a: upstream down")
+	assert.ErrorContains(t, err, "b: upstream down")
+}
+
+func TestFailoverProviderForecastBreakersOpen(t *testing.T) {
+	t.Parallel()
+
+	provider := NewFailoverProvider([]NamedProvider{
+		{Name: "static", Provider: staticProvider(domain.WeatherData{City: "Moscow"})},
+		{Name: "wttrin", Provider: failingForecaster{err: errUpstream}},
+	}, WithBreaker(1, time.Minute))
+
+	_, err := provider.GetForecast(context.Background(), "Moscow", 1)
+	assert.ErrorIs(t, err, errUpstream)
+
+	_, err = provider.GetForecast(context.Background(), "Moscow", 1)
+	assert.ErrorContains(t, err, "wttrin: временно отключен", "open breaker is reported, not a missing forecast")
+	assert.NotContains(t, err.Error(), "не поддерживает прогноз")
+
+	_, err = NewFailoverProvider([]NamedProvider{{Name: "static", Provider: staticProvider(domain.WeatherData{})}}).
+		GetForecast(context.Background(), "Moscow", 1)
+	assert.ErrorContains(t, err, "не поддерживает прогноз")
+}
+
+func TestAggregateProviderMerges(t *testing.T) {
+	t.Parallel()
+
+	provider := NewAggregateProvider([]NamedProvider{
+		{Name: "wttrin", Provider: staticProvider(domain.WeatherData{City: "Moscow", Temperature: 5, FeelsLike: 2, Humidity: 80, WindSpeed: 10, Description: "Облачно", Pressure: domain.Ptr(1018.0), UVIndex: domain.Ptr(2), Precipitation: domain.Ptr(0.0)})},
+		{Name: "open-meteo", Provider: staticProvider(domain.WeatherData{City: "Москва", Temperature: 7, FeelsLike: 3, Humidity: 70, WindSpeed: 14, Description: "Пасмурно", Pressure: domain.Ptr(1016.0), UVIndex: domain.Ptr(1), Precipitation: domain.Ptr(0.0), WindDirection: "SSW", WindDegree: domain.Ptr(200)})},
+		{Name: "owm", Provider: staticProvider(domain.WeatherData{City: "Москва", Temperature: 6, FeelsLike: 5, Humidity: 75, WindSpeed: 12, Description: "Пасмурно", Pressure: domain.Ptr(1020.0), Precipitation: domain.Ptr(0.4), WindDirection: "SW", WindDegree: domain.Ptr(210)})},
+	})
+
+	data, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "Moscow", data.City, "city comes from the highest-priority source")
+	assert.Equal(t, 6.0, data.Temperature)
+	assert.Equal(t, 3.0, data.FeelsLike)
+	assert.Equal(t, 75, data.Humidity)
+	assert.Equal(t, 12.0, data.WindSpeed)
+	assert.Equal(t, "Пасмурно", data.Description)
+	assert.Equal(t, domain.Ptr(1018.0), data.Pressure)
+	assert.Equal(t, domain.Ptr(2), data.UVIndex, "missing UV index does not drag the median down")
+	assert.Equal(t, domain.Ptr(0.0), data.Precipitation, "zero precipitation is a reading, not a gap")
+	assert.Nil(t, data.CloudCover, "no source reported cloud cover")
+	assert.Equal(t, "SSW", data.WindDirection)
+	assert.Equal(t, domain.Ptr(200), data.WindDegree)
+	assert.Equal(t, []string{"wttrin", "open-meteo", "owm"}, data.Sources)
+}
+
+func TestAggregateProviderPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	providers := []NamedProvider{
+		{Name: "a", Provider: staticProvider(domain.WeatherData{City: "Moscow", Temperature: 4, Description: "Ясно"})},
+		{Name: "b", Provider: failingProvider(errUpstream)},
+		{Name: "c", Provider: staticProvider(domain.WeatherData{City: "Moscow", Temperature: 8, Description: "Облачно"})},
+	}
+
+	data, err := NewAggregateProvider(providers).GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, data.Temperature, "median of two values is their mean")
+	assert.Equal(t, "Ясно", data.Description, "tie goes to the higher-priority source")
+	assert.Equal(t, []string{"a", "c"}, data.Sources)
+
+	_, err = NewAggregateProvider(providers, WithMinSources(3)).GetWeather(context.Background(), "Moscow")
+	assert.ErrorIs(t, err, errUpstream)
+	assert.ErrorContains(t, err, "ответили 2 из 3")
+}
+
+func TestAggregateProviderTimeout(t *testing.T) {
+	t.Parallel()
+
+	// провайдер, который игнорирует контекст, не должен задерживать ответ
+	blocked := make(chan struct{})
+	defer close(blocked)
+	stuck := providerFunc(func(ctx context.Context, city string) (*domain.WeatherData, error) {
+		<-blocked
+		return nil, errUpstream
+	})
+
+	provider := NewAggregateProvider([]NamedProvider{
+		{Name: "fast", Provider: staticProvider(domain.WeatherData{City: "Moscow", Temperature: 3})},
+		{Name: "slow", Provider: hangingProvider()},
+		{Name: "stuck", Provider: stuck},
+	}, WithProviderTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	data, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []string{"fast"}, data.Sources)
+}
+
+func TestAggregateProviderNoTimeout(t *testing.T) {
+	t.Parallel()
+
+	provider := NewAggregateProvider([]NamedProvider{
+		{Name: "fast", Provider: staticProvider(domain.WeatherData{City: "Moscow", Temperature: 3})},
+	}, WithProviderTimeout(0))
+
+	data, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err, "zero timeout means no timeout, not an expired context")
+	assert.Equal(t, []string{"fast"}, data.Sources)
+}
+
+func TestAggregateProviderCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	provider := NewAggregateProvider([]NamedProvider{{Name: "slow", Provider: hangingProvider()}})
+	_, err := provider.GetWeather(ctx, "Moscow")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMedianAndMajority(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 2.0, median([]float64{3, 1, 2}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 2, 3}))
+	assert.Equal(t, "b", majority([]string{"a", "b", "b"}))
+	assert.Equal(t, "a", majority([]string{"a", "b", "b", "a"}))
+	assert.Equal(t, "Light rain", majority([]string{"Partly cloudy", "Light rain", "light  rain "}),
+		"descriptions differing only in case and spaces are one vote")
+}
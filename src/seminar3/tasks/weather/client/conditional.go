@@ -0,0 +1,92 @@
+package client
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// validated последний ответ на адрес и его валидаторы для условного запроса
+type validated struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// validators помнит ETag и Last-Modified ответов: при повторном запросе сервер
+// может ответить 304 Not Modified без тела, и мы используем сохраненное
+type validators struct {
+	mu    sync.Mutex
+	items map[string]validated
+}
+
+func newValidators() *validators {
+	return &validators{items: make(map[string]validated)}
+}
+
+// apply добавляет к запросу на url If-None-Match и If-Modified-Since
+func (v *validators) apply(req *http.Request, url string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	item, ok := v.items[url]
+	if !ok {
+		return
+	}
+	if item.etag != "" {
+		req.Header.Set("If-None-Match", item.etag)
+	}
+	if item.lastModified != "" {
+		req.Header.Set("If-Modified-Since", item.lastModified)
+	}
+}
+
+func (v *validators) get(url string) ([]byte, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	item, ok := v.items[url]
+	return item.body, ok
+}
+
+// set запоминает ответ, если у него есть валидаторы
+func (v *validators) set(url string, header http.Header, body []byte) {
+	item := validated{etag: header.Get("ETag"), lastModified: header.Get("Last-Modified"), body: body}
+	if item.etag == "" && item.lastModified == "" {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	// городов обычно немного; при переполнении забываем произвольный адрес
+	if _, ok := v.items[url]; !ok && len(v.items) >= defaultCacheSize {
+		for key := range v.items {
+			delete(v.items, key)
+			break
+		}
+	}
+	v.items[url] = item
+}
+
+// readBody читает тело ответа, распаковывая gzip. Accept-Encoding мы задаем
+// сами, поэтому http.Transport тело не распаковывает.
+func readBody(resp *http.Response) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка распаковки ответа: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ответа: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWttrInProviderConditionalGzip(t *testing.T) {
+	t.Parallel()
+
+	var full, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") != "" {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&full, 1)
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Sun, 05 Oct 2025 09:05:00 GMT")
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write([]byte(moscowJSON))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(moscowJSON))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	provider := NewWttrInProvider(WithBaseURL(server.URL), WithRetryPolicy(NoRetry()), WithRateLimiter(nil))
+
+	for range 2 {
+		data, err := provider.GetWeather(context.Background(), "Moscow")
+		require.NoError(t, err)
+		assert.Equal(t, "Moscow", data.City)
+		assert.Equal(t, 5.0, data.Temperature)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&full))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&notModified), "second request is answered with 304")
+}
+
+func TestWttrInProviderUnexpectedNotModified(t *testing.T) {
+	t.Parallel()
+
+	provider, _, _ := newTestProvider(t, []func(http.ResponseWriter){respond(http.StatusNotModified, "")}, WithRetryPolicy(NoRetry()))
+
+	// без сохраненного ответа 304 - ошибка, а не пустые данные
+	_, err := provider.GetWeather(context.Background(), "Moscow")
+	assert.ErrorContains(t, err, "304")
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Ошибки провайдеров, которые можно проверить через errors.Is
+var (
+	ErrCityNotFound        = errors.New("город не найден")
+	ErrRateLimited         = errors.New("превышен лимит запросов к сервису погоды")
+	ErrUpstreamUnavailable = errors.New("сервис погоды недоступен")
+	ErrUnsupportedLocation = errors.New("провайдер не поддерживает такой способ задать местоположение")
+)
+
+// ParseError ответ сервиса не соответствует ожидаемой схеме.
+// Field путь к полю в ответе, Raw исходное значение (может быть обрезано).
+type ParseError struct {
+	Field string
+	Raw   string
+	Err   error
+}
+
+const maxParseErrorRaw = 64
+
+func newParseError(field, raw string, err error) *ParseError {
+	if len(raw) > maxParseErrorRaw {
+		raw = raw[:maxParseErrorRaw] + "..."
+	}
+	return &ParseError{Field: field, Raw: raw, Err: err}
+}
+
+func (e *ParseError) Error() string {
+	message := fmt.Sprintf("некорректный ответ сервиса: поле %s", e.Field)
+	if e.Raw != "" {
+		message += fmt.Sprintf(" = %q", e.Raw)
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Is сопоставляет код ответа с ошибками ErrCityNotFound, ErrRateLimited и ErrUpstreamUnavailable
+func (e *statusError) Is(target error) bool {
+	switch target {
+	case ErrCityNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUpstreamUnavailable:
+		return e.StatusCode == http.StatusRequestTimeout || e.StatusCode >= 500
+	default:
+		return false
+	}
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWttrInTypedErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response func(http.ResponseWriter)
+		target   error
+	}{
+		{"unknown city", respond(http.StatusNotFound, "Unknown location"), ErrCityNotFound},
+		{"rate limited", respond(http.StatusTooManyRequests, ""), ErrRateLimited},
+		{"server error", respond(http.StatusServiceUnavailable, ""), ErrUpstreamUnavailable},
+		{"gateway timeout", respond(http.StatusGatewayTimeout, ""), ErrUpstreamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, _, _ := newTestProvider(t, []func(http.ResponseWriter){tt.response}, WithRetryPolicy(NoRetry()))
+			_, err := provider.GetWeather(context.Background(), "Moscow")
+
+			assert.ErrorIs(t, err, tt.target)
+			for _, other := range []error{ErrCityNotFound, ErrRateLimited, ErrUpstreamUnavailable} {
+				if other != tt.target {
+					assert.NotErrorIs(t, err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestWttrInNetworkErrorIsUnavailable(t *testing.T) {
+	t.Parallel()
+
+	provider := NewWttrInProvider(WithRetryPolicy(NoRetry()), WithRateLimiter(nil))
+	provider.baseURL = "http://127.0.0.1:1/%s"
+
+	_, err := provider.GetWeather(context.Background(), "Moscow")
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+func TestWttrInValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+		raw   string
+	}{
+		{
+			name:  "broken JSON",
+			body:  "{not json",
+			field: "body",
+			raw:   "{not json",
+		},
+		{
+			name:  "no current condition",
+			body:  `{"current_condition": [], "nearest_area": []}`,
+			field: "current_condition",
+		},
+		{
+			name:  "no description",
+			body:  `{"current_condition": [{"temp_C": "5", "humidity": "80", "windspeedKmph": "10", "FeelsLikeC": "2", "weatherDesc": []}]}`,
+			field: "current_condition[0].weatherDesc",
+		},
+		{
+			name:  "missing field",
+			body:  `{"current_condition": [{"humidity": "80", "windspeedKmph": "10", "FeelsLikeC": "2", "weatherDesc": [{"value": "Cloudy"}]}]}`,
+			field: "current_condition[0].temp_C",
+		},
+		{
+			name:  "malformed number",
+			body:  `{"current_condition": [{"temp_C": "warm", "humidity": "80", "windspeedKmph": "10", "FeelsLikeC": "2", "weatherDesc": [{"value": "Cloudy"}]}]}`,
+			field: "current_condition[0].temp_C",
+			raw:   "warm",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, _, _ := newTestProvider(t, []func(http.ResponseWriter){respond(http.StatusOK, tt.body)})
+
+			var data any
+			var err error
+			require.NotPanics(t, func() {
+				data, err = provider.GetWeather(context.Background(), "Moscow")
+			})
+			assert.Nil(t, data)
+
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr), "got %v", err)
+			assert.Equal(t, tt.field, parseErr.Field)
+			assert.Equal(t, tt.raw, parseErr.Raw)
+		})
+	}
+}
+
+func TestWttrInForecastValidation(t *testing.T) {
+	t.Parallel()
+
+	body := `{"weather": [{"date": "2025-01-01", "mintempC": "1", "maxtempC": "3", "avgtempC": "2",
+		"hourly": [{"time": "0", "tempC": "cold", "FeelsLikeC": "0", "humidity": "80", "windspeedKmph": "5"}]}]}`
+	provider, _, _ := newTestProvider(t, []func(http.ResponseWriter){respond(http.StatusOK, body)})
+
+	_, err := provider.GetForecast(context.Background(), "Moscow", 1)
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "weather[0].hourly[0].tempC", parseErr.Field)
+	assert.Equal(t, "cold", parseErr.Raw)
+
+	_, err = provider.GetForecast(context.Background(), "Moscow", 2)
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "weather", parseErr.Field)
+}
+
+func TestParseObservation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		local    string
+		utc      string
+		expected string
+	}{
+		{"2025-10-05 12:05 PM", "09:05 AM", "2025-10-05T12:05:00+03:00"},
+		{"2025-10-05 01:30 AM", "08:00 PM", "2025-10-05T01:30:00+05:30"},
+		{"2025-10-04 08:00 PM", "03:00 AM", "2025-10-04T20:00:00-07:00"},
+		{"2025-10-05 09:05 AM", "", "2025-10-05T09:05:00Z"},
+	}
+
+	for _, tt := range tests {
+		observed, err := parseObservation(tt.local, tt.utc)
+		require.NoError(t, err, tt.local)
+		assert.Equal(t, tt.expected, observed.Format(time.RFC3339), tt.local)
+	}
+
+	_, err := parseObservation("yesterday", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+var errUpstream = errors.New("upstream down")
+
+// fakeProvider отдает заранее заданные данные и считает вызовы
+type fakeProvider struct {
+	mu    sync.Mutex
+	data  map[string]domain.WeatherData
+	err   error
+	calls []string
+}
+
+func newFakeProvider(cities ...domain.WeatherData) *fakeProvider {
+	p := &fakeProvider{data: make(map[string]domain.WeatherData)}
+	for _, city := range cities {
+		p.data[city.City] = city
+	}
+	return p
+}
+
+func (p *fakeProvider) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls = append(p.calls, city)
+	if p.err != nil {
+		return nil, p.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, ok := p.data[city]
+	if !ok {
+		return nil, errors.New("city not found")
+	}
+	return &data, nil
+}
+
+func (p *fakeProvider) setErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+func (p *fakeProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.calls)
+}
+
+// providerFunc позволяет описать провайдер одной функцией
+type providerFunc func(ctx context.Context, city string) (*domain.WeatherData, error)
+
+func (f providerFunc) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	return f(ctx, city)
+}
+
+// staticProvider всегда отвечает одними и теми же данными
+func staticProvider(data domain.WeatherData) providerFunc {
+	return func(ctx context.Context, city string) (*domain.WeatherData, error) {
+		result := data
+		return &result, nil
+	}
+}
+
+// failingProvider всегда возвращает err
+func failingProvider(err error) providerFunc {
+	return func(ctx context.Context, city string) (*domain.WeatherData, error) {
+		return nil, err
+	}
+}
+
+// hangingProvider ждет отмены контекста
+func hangingProvider() providerFunc {
+	return func(ctx context.Context, city string) (*domain.WeatherData, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+}
+
+// failingForecaster поддерживает прогноз, но и погода, и прогноз возвращают err
+type failingForecaster struct {
+	err error
+}
+
+func (f failingForecaster) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	return nil, f.err
+}
+
+func (f failingForecaster) GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error) {
+	return nil, f.err
+}
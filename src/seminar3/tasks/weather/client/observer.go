@@ -0,0 +1,82 @@
+package client
+
+import (
+	"log/slog"
+	"time"
+)
+
+// AttemptEvent сведения об одной попытке запроса к wttr.in
+type AttemptEvent struct {
+	City        string
+	URL         string
+	Attempt     int           // номер попытки, начиная с 1
+	MaxAttempts int           // сколько попыток разрешает политика
+	Duration    time.Duration // длительность попытки; 0 в OnAttempt
+	Err         error         // ошибка попытки; в OnFailure - итоговая ошибка запроса
+}
+
+// Observer получает события о ходе запроса. Методы вызываются синхронно
+// в горутине запроса, поэтому не должны надолго блокироваться.
+type Observer interface {
+	OnAttempt(event AttemptEvent)                    // попытка начинается
+	OnRetry(event AttemptEvent, delay time.Duration) // попытка не удалась, следующая через delay
+	OnSuccess(event AttemptEvent)                    // данные получены
+	OnFailure(event AttemptEvent)                    // запрос окончательно не удался
+}
+
+// nopObserver наблюдатель по умолчанию: ничего не выводит
+type nopObserver struct{}
+
+func (nopObserver) OnAttempt(AttemptEvent)              {}
+func (nopObserver) OnRetry(AttemptEvent, time.Duration) {}
+func (nopObserver) OnSuccess(AttemptEvent)              {}
+func (nopObserver) OnFailure(AttemptEvent)              {}
+
+// WithObserver подключает наблюдатель за попытками запроса
+func WithObserver(observer Observer) Option {
+	return func(w *WttrInProvider) {
+		if observer == nil {
+			observer = nopObserver{}
+		}
+		w.observer = observer
+	}
+}
+
+// WithLogger пишет ход запроса в logger: попытки на уровне Debug,
+// повторы на уровне Warn, окончательную ошибку на уровне Error; nil отключает журнал
+func WithLogger(logger *slog.Logger) Option {
+	if logger == nil {
+		return WithObserver(nil)
+	}
+	return WithObserver(NewSlogObserver(logger))
+}
+
+// SlogObserver Observer, который пишет события в *slog.Logger
+type SlogObserver struct {
+	logger *slog.Logger
+}
+
+func NewSlogObserver(logger *slog.Logger) *SlogObserver {
+	return &SlogObserver{logger: logger}
+}
+
+func (o *SlogObserver) OnAttempt(event AttemptEvent) {
+	o.logger.Debug("запрос погоды",
+		"city", event.City, "attempt", event.Attempt, "max_attempts", event.MaxAttempts)
+}
+
+func (o *SlogObserver) OnRetry(event AttemptEvent, delay time.Duration) {
+	o.logger.Warn("попытка неудачна, повторяю",
+		"city", event.City, "attempt", event.Attempt, "duration", event.Duration,
+		"retry_in", delay, "error", event.Err)
+}
+
+func (o *SlogObserver) OnSuccess(event AttemptEvent) {
+	o.logger.Info("данные получены",
+		"city", event.City, "attempt", event.Attempt, "duration", event.Duration)
+}
+
+func (o *SlogObserver) OnFailure(event AttemptEvent) {
+	o.logger.Error("не удалось получить данные",
+		"city", event.City, "attempt", event.Attempt, "duration", event.Duration, "error", event.Err)
+}
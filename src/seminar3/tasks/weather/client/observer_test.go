@@ -0,0 +1,123 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingObserver записывает события в виде строк
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+	last   AttemptEvent
+}
+
+func (o *recordingObserver) record(name string, event AttemptEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf("%s %d/%d", name, event.Attempt, event.MaxAttempts))
+	o.last = event
+}
+
+func (o *recordingObserver) OnAttempt(event AttemptEvent) { o.record("attempt", event) }
+func (o *recordingObserver) OnSuccess(event AttemptEvent) { o.record("success", event) }
+func (o *recordingObserver) OnFailure(event AttemptEvent) { o.record("failure", event) }
+func (o *recordingObserver) OnRetry(event AttemptEvent, delay time.Duration) {
+	o.record(fmt.Sprintf("retry in %v", delay), event)
+}
+
+func TestObserverEvents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		responses []func(http.ResponseWriter)
+		expected  []string
+		lastErr   bool
+	}{
+		{
+			name:      "success after retry",
+			responses: []func(http.ResponseWriter){respond(http.StatusServiceUnavailable, ""), respond(http.StatusOK, moscowJSON)},
+			expected:  []string{"attempt 1/4", "retry in 500ms 1/4", "attempt 2/4", "success 2/4"},
+		},
+		{
+			name:      "permanent failure",
+			responses: []func(http.ResponseWriter){respond(http.StatusNotFound, "")},
+			expected:  []string{"attempt 1/4", "failure 1/4"},
+			lastErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			observer := &recordingObserver{}
+			provider, _, _ := newTestProvider(t, tt.responses, WithObserver(observer))
+
+			_, err := provider.GetWeather(context.Background(), "Moscow")
+			assert.Equal(t, tt.lastErr, err != nil)
+			assert.Equal(t, tt.expected, observer.events)
+
+			assert.Equal(t, "Moscow", observer.last.City)
+			assert.Positive(t, observer.last.Duration, "attempt timing is reported")
+			if tt.lastErr {
+				assert.Equal(t, err, observer.last.Err, "failure carries the returned error")
+			}
+		})
+	}
+}
+
+func TestSlogObserver(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	provider, _, _ := newTestProvider(t,
+		[]func(http.ResponseWriter){respond(http.StatusBadGateway, ""), respond(http.StatusOK, moscowJSON)},
+		WithLogger(logger),
+	)
+
+	_, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 4)
+
+	var retry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &retry))
+	assert.Equal(t, "WARN", retry["level"])
+	assert.Equal(t, "Moscow", retry["city"])
+	assert.Contains(t, retry, "duration")
+	assert.Contains(t, retry["error"], "502")
+
+	var success map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[3]), &success))
+	assert.Equal(t, "INFO", success["level"])
+	assert.Equal(t, 2.0, success["attempt"])
+}
+
+func TestWithNilLogger(t *testing.T) {
+	t.Parallel()
+
+	provider, _, _ := newTestProvider(t,
+		[]func(http.ResponseWriter){respond(http.StatusBadGateway, ""), respond(http.StatusOK, moscowJSON)},
+		WithLogger(nil),
+	)
+
+	assert.NotPanics(t, func() {
+		_, err := provider.GetWeather(context.Background(), "Moscow")
+		assert.NoError(t, err)
+	})
+}
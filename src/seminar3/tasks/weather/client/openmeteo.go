@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+const (
+	openMeteoGeocodingURL = "https://geocoding-api.open-meteo.com/v1/search"
+	openMeteoForecastURL  = "https://api.open-meteo.com/v1/forecast"
+	openMeteoCurrentVars  = "temperature_2m,relative_humidity_2m,apparent_temperature,weather_code,wind_speed_10m," +
+		"wind_direction_10m,pressure_msl,cloud_cover,precipitation,visibility,uv_index"
+)
+
+// OpenMeteoProvider реализация для open-meteo.com: сначала геокодинг города, затем погода по координатам.
+// API ключ не нужен.
+type OpenMeteoProvider struct {
+	client       *http.Client
+	geocodingURL string
+	forecastURL  string
+	language     string
+}
+
+type OpenMeteoOption func(*OpenMeteoProvider)
+
+// WithOpenMeteoLanguage язык названий городов и описаний погоды: "ru" (по умолчанию) или "en"
+func WithOpenMeteoLanguage(language string) OpenMeteoOption {
+	return func(o *OpenMeteoProvider) {
+		if language != "" {
+			o.language = language
+		}
+	}
+}
+
+func NewOpenMeteoProvider(options ...OpenMeteoOption) *OpenMeteoProvider {
+	o := &OpenMeteoProvider{
+		client:       &http.Client{Timeout: DefaultTimeout},
+		geocodingURL: openMeteoGeocodingURL,
+		forecastURL:  openMeteoForecastURL,
+		language:     "ru",
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+func (o *OpenMeteoProvider) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	location, err := o.resolve(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("latitude", strconv.FormatFloat(location.Latitude, 'f', 4, 64))
+	query.Set("longitude", strconv.FormatFloat(location.Longitude, 'f', 4, 64))
+	query.Set("current", openMeteoCurrentVars)
+	query.Set("wind_speed_unit", "kmh")
+	query.Set("timezone", "auto")
+
+	var response domain.OpenMeteoForecastResponse
+	if err := getJSON(ctx, o.client, o.forecastURL+"?"+query.Encode(), &response); err != nil {
+		return nil, fmt.Errorf("open-meteo: %w", err)
+	}
+	if response.Current == nil {
+		return nil, fmt.Errorf("open-meteo: %w", newParseError("current", "", errMissing))
+	}
+
+	current := response.Current
+	data := &domain.WeatherData{
+		City:          location.Name,
+		Temperature:   current.Temperature2m,
+		Humidity:      current.RelativeHumidity2m,
+		Description:   wmoDescription(current.WeatherCode, o.language),
+		WindSpeed:     current.WindSpeed10m,
+		FeelsLike:     current.ApparentTemperature,
+		Pressure:      current.PressureMSL,
+		CloudCover:    current.CloudCover,
+		Precipitation: current.Precipitation,
+		WindDegree:    current.WindDirection10m,
+	}
+	if current.WindDirection10m != nil {
+		data.WindDirection = domain.CompassPoint(*current.WindDirection10m)
+	}
+	if current.Visibility != nil {
+		data.Visibility = domain.Ptr(*current.Visibility / 1000)
+	}
+	if current.UVIndex != nil {
+		data.UVIndex = domain.Ptr(int(math.Round(*current.UVIndex)))
+	}
+
+	// время приходит без пояса, в местном времени точки при timezone=auto
+	if current.Time != "" {
+		zone := time.FixedZone("", response.UTCOffsetSeconds)
+		observedAt, err := time.ParseInLocation("2006-01-02T15:04", current.Time, zone)
+		if err != nil {
+			return nil, fmt.Errorf("open-meteo: %w", newParseError("current.time", current.Time, err))
+		}
+		data.ObservedAt = observedAt
+	}
+
+	return data, nil
+}
+
+// resolve превращает строку местоположения в координаты; для координат геокодинг не нужен
+func (o *OpenMeteoProvider) resolve(ctx context.Context, query string) (*domain.OpenMeteoLocation, error) {
+	location, err := domain.ParseLocation(query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch location.Kind {
+	case domain.LocationCity:
+		return o.geocode(ctx, location.Name)
+	case domain.LocationCoordinates:
+		return &domain.OpenMeteoLocation{
+			Name:      location.String(),
+			Latitude:  location.Latitude,
+			Longitude: location.Longitude,
+		}, nil
+	default:
+		return nil, fmt.Errorf("open-meteo: %w: %s", ErrUnsupportedLocation, location.Kind)
+	}
+}
+
+// geocode находит координаты города
+func (o *OpenMeteoProvider) geocode(ctx context.Context, city string) (*domain.OpenMeteoLocation, error) {
+	query := url.Values{}
+	query.Set("name", city)
+	query.Set("count", "1")
+	query.Set("language", o.language)
+	query.Set("format", "json")
+
+	var response domain.OpenMeteoGeocodingResponse
+	if err := getJSON(ctx, o.client, o.geocodingURL+"?"+query.Encode(), &response); err != nil {
+		return nil, fmt.Errorf("open-meteo геокодинг: %w", err)
+	}
+	if len(response.Results) == 0 {
+		return nil, fmt.Errorf("open-meteo: %w: %q", ErrCityNotFound, city)
+	}
+
+	return &response.Results[0], nil
+}
+
+// wmoDescriptions описания погоды по кодам WMO; коды одной группы описываются одинаково
+var wmoDescriptions = []struct {
+	codes  []int
+	ru, en string
+}{
+	{[]int{0}, "Ясно", "Clear sky"},
+	{[]int{1}, "Преимущественно ясно", "Mainly clear"},
+	{[]int{2}, "Переменная облачность", "Partly cloudy"},
+	{[]int{3}, "Пасмурно", "Overcast"},
+	{[]int{45, 48}, "Туман", "Fog"},
+	{[]int{51, 53, 55}, "Морось", "Drizzle"},
+	{[]int{56, 57}, "Ледяная морось", "Freezing drizzle"},
+	{[]int{61, 63, 65}, "Дождь", "Rain"},
+	{[]int{66, 67}, "Ледяной дождь", "Freezing rain"},
+	{[]int{71, 73, 75, 77}, "Снег", "Snow"},
+	{[]int{80, 81, 82}, "Ливень", "Rain showers"},
+	{[]int{85, 86}, "Снегопад", "Snow showers"},
+	{[]int{95}, "Гроза", "Thunderstorm"},
+	{[]int{96, 99}, "Гроза с градом", "Thunderstorm with hail"},
+}
+
+// wmoDescription описание погоды по коду WMO, который возвращает open-meteo;
+// для языков, кроме русского, описание на английском
+func wmoDescription(code int, language string) string {
+	for _, d := range wmoDescriptions {
+		if slices.Contains(d.codes, code) {
+			if language == "ru" {
+				return d.ru
+			}
+			return d.en
+		}
+	}
+	if language == "ru" {
+		return fmt.Sprintf("Код погоды %d", code)
+	}
+	return fmt.Sprintf("Weather code %d", code)
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+const (
+	openWeatherMapURL    = "https://api.openweathermap.org/data/2.5/weather"
+	openWeatherMapKeyEnv = "OPENWEATHERMAP_API_KEY"
+)
+
+// OpenWeatherMapProvider реализация для openweathermap.org, требует API ключ
+type OpenWeatherMapProvider struct {
+	client   *http.Client
+	baseURL  string
+	apiKey   string
+	language string
+}
+
+type OpenWeatherMapOption func(*OpenWeatherMapProvider)
+
+// WithOpenWeatherMapLanguage язык названий городов и описаний погоды: "ru" (по умолчанию), "en" и т.д.
+func WithOpenWeatherMapLanguage(language string) OpenWeatherMapOption {
+	return func(o *OpenWeatherMapProvider) {
+		if language != "" {
+			o.language = language
+		}
+	}
+}
+
+func NewOpenWeatherMapProvider(apiKey string, options ...OpenWeatherMapOption) *OpenWeatherMapProvider {
+	o := &OpenWeatherMapProvider{
+		client:   &http.Client{Timeout: DefaultTimeout},
+		baseURL:  openWeatherMapURL,
+		apiKey:   apiKey,
+		language: "ru",
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// NewOpenWeatherMapProviderFromEnv берет ключ из переменной окружения OPENWEATHERMAP_API_KEY
+func NewOpenWeatherMapProviderFromEnv(options ...OpenWeatherMapOption) (*OpenWeatherMapProvider, error) {
+	apiKey := os.Getenv(openWeatherMapKeyEnv)
+	if apiKey == "" {
+		return nil, fmt.Errorf("не задан API ключ: установите переменную %s", openWeatherMapKeyEnv)
+	}
+	return NewOpenWeatherMapProvider(apiKey, options...), nil
+}
+
+func (o *OpenWeatherMapProvider) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	location, err := domain.ParseLocation(city)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	switch location.Kind {
+	case domain.LocationCity:
+		query.Set("q", location.Name)
+	case domain.LocationCoordinates:
+		query.Set("lat", strconv.FormatFloat(location.Latitude, 'f', -1, 64))
+		query.Set("lon", strconv.FormatFloat(location.Longitude, 'f', -1, 64))
+	default:
+		return nil, fmt.Errorf("openweathermap: %w: %s", ErrUnsupportedLocation, location.Kind)
+	}
+	query.Set("appid", o.apiKey)
+	query.Set("units", "metric")
+	query.Set("lang", o.language)
+
+	var response domain.OpenWeatherMapResponse
+	if err := getJSON(ctx, o.client, o.baseURL+"?"+query.Encode(), &response); err != nil {
+		return nil, fmt.Errorf("openweathermap: %w", err)
+	}
+	if response.Main == nil {
+		return nil, fmt.Errorf("openweathermap: %w", newParseError("main", "", errMissing))
+	}
+
+	var description string
+	if len(response.Weather) > 0 {
+		description = response.Weather[0].Description
+	}
+
+	cityName := response.Name
+	if cityName == "" {
+		cityName = location.String()
+	}
+
+	data := &domain.WeatherData{
+		City:          cityName,
+		Temperature:   response.Main.Temp,
+		Humidity:      response.Main.Humidity,
+		Description:   description,
+		WindSpeed:     response.Wind.Speed * 3.6, // м/с -> км/ч
+		FeelsLike:     response.Main.FeelsLike,
+		Pressure:      domain.Ptr(response.Main.Pressure),
+		Precipitation: domain.Ptr(response.Rain.OneHour + response.Snow.OneHour), // без осадков rain и snow не приходят
+		WindDegree:    response.Wind.Deg,
+	}
+	if response.Wind.Deg != nil {
+		data.WindDirection = domain.CompassPoint(*response.Wind.Deg)
+	}
+	if response.Visibility != nil {
+		data.Visibility = domain.Ptr(*response.Visibility / 1000)
+	}
+	if response.Clouds != nil {
+		data.CloudCover = domain.Ptr(response.Clouds.All)
+	}
+	if response.Dt > 0 {
+		data.ObservedAt = time.Unix(response.Dt, 0).In(time.FixedZone("", response.Timezone))
+	}
+	return data, nil
+}
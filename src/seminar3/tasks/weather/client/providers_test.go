@@ -0,0 +1,297 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// serveFixture отдает файл из testdata с кодом status
+func serveFixture(t *testing.T, status int, name string) http.HandlerFunc {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}
+}
+
+func TestWttrInProviderFixture(t *testing.T) {
+	t.Parallel()
+
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		serveFixture(t, http.StatusOK, "wttrin_moscow.json")(w, r)
+	}))
+	defer server.Close()
+
+	provider := NewWttrInProvider(WithRetryPolicy(NoRetry()), WithRateLimiter(nil))
+	provider.baseURL = server.URL + "/%s?format=j1"
+
+	data, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "/Moscow", path)
+	assert.Equal(t, "Moscow", data.City)
+	assert.Equal(t, 7.0, data.Temperature)
+	assert.Equal(t, 4.0, data.FeelsLike)
+	assert.Equal(t, 81, data.Humidity)
+	assert.Equal(t, 13.0, data.WindSpeed)
+	assert.Equal(t, "Overcast", data.Description)
+	assert.Equal(t, domain.Ptr(1018.0), data.Pressure)
+	assert.Equal(t, domain.Ptr(10.0), data.Visibility)
+	assert.Equal(t, domain.Ptr(1), data.UVIndex)
+	assert.Equal(t, domain.Ptr(100), data.CloudCover)
+	assert.Equal(t, domain.Ptr(0.0), data.Precipitation)
+	assert.Equal(t, "SSW", data.WindDirection)
+	assert.Equal(t, domain.Ptr(203), data.WindDegree)
+	assert.Equal(t, "2025-10-05T12:05:00+03:00", data.ObservedAt.Format(time.RFC3339))
+
+	forecast, err := provider.GetForecast(context.Background(), "Moscow", 3)
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 3)
+	assert.Equal(t, 3.0, forecast.Days[0].MinTemp)
+	assert.Equal(t, 9.0, forecast.Days[0].MaxTemp)
+	assert.Equal(t, "06:51 AM", forecast.Days[0].Sunrise)
+	require.Len(t, forecast.Days[0].Hourly, 4)
+	assert.Equal(t, "18:00", forecast.Days[0].Hourly[3].Time.Format("15:04"))
+	assert.Equal(t, 65, forecast.Days[0].Hourly[3].ChanceOfRain)
+
+	_, err = provider.GetForecast(context.Background(), "Moscow", 4)
+	assert.Error(t, err)
+}
+
+func TestWttrInProviderLocationPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		location string
+		expected string
+	}{
+		{location: "Moscow", expected: "/Moscow"},
+		{location: "New York", expected: "/New%20York"},
+		{location: "Москва", expected: "/%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0"},
+		{location: "SVO", expected: "/SVO"},
+		{location: "airport:SVO", expected: "/svo"},
+		{location: "airport:jfk", expected: "/jfk"},
+		{location: "55.75, 37.62", expected: "/55.75,37.62"},
+		{location: "auto", expected: "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			t.Parallel()
+
+			var path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.EscapedPath()
+				serveFixture(t, http.StatusOK, "wttrin_moscow.json")(w, r)
+			}))
+			defer server.Close()
+
+			provider := NewWttrInProvider(WithRetryPolicy(NoRetry()), WithRateLimiter(nil))
+			provider.baseURL = server.URL + "/%s?format=j1"
+
+			_, err := provider.GetWeather(context.Background(), tt.location)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+func TestWttrInProviderInvalidLocation(t *testing.T) {
+	t.Parallel()
+
+	provider, calls, _ := newTestProvider(t, []func(http.ResponseWriter){respond(http.StatusOK, moscowJSON)})
+
+	_, err := provider.GetWeather(context.Background(), "91,0")
+	assert.ErrorIs(t, err, domain.ErrInvalidLocation)
+	assert.Zero(t, *calls, "invalid location is not sent to the service")
+}
+
+func TestWttrInProviderOptions(t *testing.T) {
+	t.Parallel()
+
+	var path, userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, userAgent = r.URL.Path+"?"+r.URL.RawQuery, r.UserAgent()
+		serveFixture(t, http.StatusOK, "wttrin_moscow.json")(w, r)
+	}))
+	defer server.Close()
+
+	provider := NewWttrInProvider(
+		WithBaseURL(server.URL+"/"),
+		WithUserAgent("test-agent/2.0"),
+		WithTimeout(time.Second),
+		WithRetryPolicy(NoRetry()),
+		WithRateLimiter(nil),
+	)
+
+	_, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "/Moscow?format=j1", path)
+	assert.Equal(t, "test-agent/2.0", userAgent)
+	assert.Equal(t, time.Second, provider.client.Timeout)
+
+	defaults := NewWttrInProvider(WithBaseURL(""), WithUserAgent(""))
+	assert.Equal(t, DefaultWttrInURL+wttrInPath, defaults.baseURL, "empty values keep the defaults")
+	assert.Equal(t, DefaultUserAgent, defaults.userAgent)
+}
+
+func TestOpenMeteoProvider(t *testing.T) {
+	t.Parallel()
+
+	var geocodedName, latitude string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		geocodedName = r.URL.Query().Get("name")
+		if geocodedName == "Атлантида" {
+			serveFixture(t, http.StatusOK, "openmeteo_geocoding_empty.json")(w, r)
+			return
+		}
+		serveFixture(t, http.StatusOK, "openmeteo_geocoding_moscow.json")(w, r)
+	})
+	mux.HandleFunc("/v1/forecast", func(w http.ResponseWriter, r *http.Request) {
+		latitude = r.URL.Query().Get("latitude")
+		serveFixture(t, http.StatusOK, "openmeteo_forecast_moscow.json")(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider := NewOpenMeteoProvider()
+	provider.geocodingURL = server.URL + "/v1/search"
+	provider.forecastURL = server.URL + "/v1/forecast"
+
+	data, err := provider.GetWeather(context.Background(), "Москва")
+	require.NoError(t, err)
+	assert.Equal(t, "Москва", geocodedName)
+	assert.Equal(t, "55.7522", latitude)
+	assert.Equal(t, "Москва", data.City)
+	assert.Equal(t, 7.4, data.Temperature)
+	assert.Equal(t, 4.2, data.FeelsLike)
+	assert.Equal(t, 76, data.Humidity)
+	assert.Equal(t, 13.3, data.WindSpeed)
+	assert.Equal(t, "Пасмурно", data.Description)
+	assert.Equal(t, domain.Ptr(1017.6), data.Pressure)
+	assert.Equal(t, domain.Ptr(24.14), data.Visibility)
+	assert.Equal(t, domain.Ptr(1), data.UVIndex)
+	assert.Equal(t, domain.Ptr(100), data.CloudCover)
+	assert.Equal(t, domain.Ptr(0.1), data.Precipitation)
+	assert.Equal(t, "SSW", data.WindDirection)
+	assert.Equal(t, "2025-10-05T12:00:00+03:00", data.ObservedAt.Format(time.RFC3339))
+
+	_, err = provider.GetWeather(context.Background(), "Атлантида")
+	assert.ErrorIs(t, err, ErrCityNotFound)
+
+	geocodedName = ""
+	data, err = provider.GetWeather(context.Background(), "48.8566,2.3522")
+	require.NoError(t, err)
+	assert.Empty(t, geocodedName, "coordinates are not geocoded")
+	assert.Equal(t, "48.8566", latitude)
+	assert.Equal(t, "48.8566,2.3522", data.City)
+
+	_, err = provider.GetWeather(context.Background(), "airport:SVO")
+	assert.ErrorIs(t, err, ErrUnsupportedLocation)
+}
+
+func TestWMODescription(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code     int
+		language string
+		expected string
+	}{
+		{code: 3, language: "ru", expected: "Пасмурно"},
+		{code: 3, language: "en", expected: "Overcast"},
+		{code: 75, language: "en", expected: "Snow"},
+		{code: 99, language: "de", expected: "Thunderstorm with hail"},
+		{code: 42, language: "ru", expected: "Код погоды 42"},
+		{code: 42, language: "en", expected: "Weather code 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language+"/"+tt.expected, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, wmoDescription(tt.code, tt.language))
+		})
+	}
+}
+
+func TestOpenWeatherMapProvider(t *testing.T) {
+	t.Parallel()
+
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{
+			"q":     r.URL.Query().Get("q"),
+			"appid": r.URL.Query().Get("appid"),
+			"units": r.URL.Query().Get("units"),
+			"lang":  r.URL.Query().Get("lang"),
+		}
+		if query["appid"] != "secret" {
+			serveFixture(t, http.StatusUnauthorized, "openweathermap_unauthorized.json")(w, r)
+			return
+		}
+		serveFixture(t, http.StatusOK, "openweathermap_moscow.json")(w, r)
+	}))
+	defer server.Close()
+
+	provider := NewOpenWeatherMapProvider("secret", WithOpenWeatherMapLanguage("en"))
+	provider.baseURL = server.URL
+
+	data, err := provider.GetWeather(context.Background(), "New York")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"q": "New York", "appid": "secret", "units": "metric", "lang": "en"}, query)
+	assert.Equal(t, "Москва", data.City)
+	assert.Equal(t, 7.1, data.Temperature)
+	assert.Equal(t, 74, data.Humidity)
+	assert.InDelta(t, 12.6, data.WindSpeed, 1e-9, "m/s converted to km/h")
+	assert.Equal(t, "пасмурно", data.Description)
+	assert.Equal(t, domain.Ptr(1018.0), data.Pressure)
+	assert.Equal(t, domain.Ptr(10.0), data.Visibility)
+	assert.Equal(t, domain.Ptr(100), data.CloudCover)
+	assert.Equal(t, "SSW", data.WindDirection)
+	assert.Equal(t, domain.Ptr(210), data.WindDegree)
+	assert.Equal(t, "2025-10-05T13:00:00+03:00", data.ObservedAt.Format(time.RFC3339))
+
+	provider.apiKey = "wrong"
+	_, err = provider.GetWeather(context.Background(), "Moscow")
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestOpenWeatherMapProviderFromEnv(t *testing.T) {
+	t.Setenv(openWeatherMapKeyEnv, "")
+	_, err := NewOpenWeatherMapProviderFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv(openWeatherMapKeyEnv, "key")
+	provider, err := NewOpenWeatherMapProviderFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "key", provider.apiKey)
+}
+
+func TestOpenWeatherMapHidesKeyInErrors(t *testing.T) {
+	t.Parallel()
+
+	provider := NewOpenWeatherMapProvider("topsecret")
+	provider.baseURL = "http://127.0.0.1:1"
+
+	_, err := provider.GetWeather(context.Background(), "Moscow")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "topsecret")
+}
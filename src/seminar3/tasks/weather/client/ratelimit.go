@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRateInterval wttr.in спокойно относится к запросу раз в секунду
+	DefaultRateInterval = time.Second
+	// DefaultRateBurst сколько запросов можно сделать подряд без ожидания
+	DefaultRateBurst = 5
+)
+
+// defaultLimiter общий ограничитель провайдеров, созданных без WithRateLimiter:
+// все они ходят в один сервис, и пакетные запросы не должны упираться в 429
+var defaultLimiter = sync.OnceValue(func() *RateLimiter {
+	return NewRateLimiter(DefaultRateInterval, DefaultRateBurst)
+})
+
+// RateLimiter ограничивает частоту запросов алгоритмом token bucket: токен
+// добавляется раз в interval, в корзине помещается не больше burst токенов.
+// Один RateLimiter разделяют все горутины, работающие с провайдером, и
+// несколько провайдеров, если они ходят в один сервис.
+type RateLimiter struct {
+	interval time.Duration
+	burst    int
+	now      func() time.Time
+	sleep    Sleeper
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// RateLimitState текущее состояние ограничителя
+type RateLimitState struct {
+	Tokens      float64       // доступно токенов; отрицательно, если запросы уже стоят в очереди
+	Burst       int           // емкость корзины
+	Interval    time.Duration // период пополнения на один токен
+	PausedUntil time.Time     // сервер попросил не обращаться до этого момента (Retry-After)
+	Wait        time.Duration // сколько ждать следующему запросу
+}
+
+// Limited запросы придется ждать
+func (s RateLimitState) Limited() bool {
+	return s.Wait > 0
+}
+
+// RateLimited провайдер, который сообщает состояние своего ограничителя;
+// false означает, что ограничения нет
+type RateLimited interface {
+	RateLimit() (RateLimitState, bool)
+}
+
+type RateLimiterOption func(*RateLimiter)
+
+// WithLimiterClock подменяет часы и ожидание, в основном для тестов
+func WithLimiterClock(now func() time.Time, sleep Sleeper) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.now = now
+		l.sleep = sleep
+	}
+}
+
+// NewRateLimiter разрешает burst запросов сразу и далее один запрос в interval
+func NewRateLimiter(interval time.Duration, burst int, options ...RateLimiterOption) *RateLimiter {
+	l := &RateLimiter{
+		interval: interval,
+		burst:    max(burst, 1),
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+	for _, option := range options {
+		option(l)
+	}
+
+	l.tokens = float64(l.burst)
+	l.last = l.now()
+	return l
+}
+
+// Wait ждет своей очереди на запрос. Если ждать пришлось бы дольше срока ctx,
+// сразу возвращает ErrRateLimited, не занимая очередь.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.now()
+	l.refill(now)
+	wait := l.wait(now)
+	if deadline, ok := ctx.Deadline(); ok && now.Add(wait).After(deadline) {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: следующий запрос возможен через %v", ErrRateLimited, wait.Round(time.Millisecond))
+	}
+	// токен занимаем сразу, чтобы ожидающие горутины выстроились в очередь
+	l.tokens--
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := l.sleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Pause приостанавливает запросы на d, например по заголовку Retry-After.
+// Более короткая пауза не отменяет уже назначенную. Корзина начинает
+// пополняться только с конца паузы, и в ней остается не больше одного токена,
+// поэтому запросы, вставшие в очередь во время паузы, идут после нее
+// по одному в interval, а не все разом.
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	until := now.Add(d)
+	if !until.After(l.pausedUntil) {
+		return
+	}
+	l.refill(now)
+	l.pausedUntil = until
+	l.tokens = min(l.tokens, 1)
+	l.last = until
+}
+
+// State текущее состояние, чтобы вызывающий мог заранее снизить нагрузку
+func (l *RateLimiter) State() RateLimitState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.refill(now)
+	state := RateLimitState{
+		Tokens:   l.tokens,
+		Burst:    l.burst,
+		Interval: l.interval,
+		Wait:     l.wait(now),
+	}
+	if l.pausedUntil.After(now) {
+		state.PausedUntil = l.pausedUntil
+	}
+	return state
+}
+
+// refill добавляет токены, накопившиеся с прошлого обращения; во время паузы
+// last в будущем и токены не добавляются
+func (l *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		if l.interval > 0 {
+			l.tokens = min(l.tokens+float64(elapsed)/float64(l.interval), float64(l.burst))
+		} else {
+			l.tokens = float64(l.burst)
+		}
+		l.last = now
+	}
+}
+
+// wait сколько ждать до следующего токена с учетом паузы по Retry-After:
+// отсчет пополнения идет от last, который Pause переносит на конец паузы
+func (l *RateLimiter) wait(now time.Time) time.Duration {
+	wait := max(l.last.Sub(now), 0)
+	if l.tokens < 1 {
+		wait += time.Duration((1 - l.tokens) * float64(l.interval))
+	}
+	return wait
+}
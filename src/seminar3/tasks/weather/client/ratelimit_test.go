@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limiterClock часы, которые идут только по advance; sleep запоминает задержки и не ждет
+type limiterClock struct {
+	mu     sync.Mutex
+	at     time.Time
+	delays []time.Duration
+}
+
+func newLimiterClock() *limiterClock {
+	return &limiterClock{at: time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *limiterClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.at
+}
+
+func (c *limiterClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.at = c.at.Add(d)
+}
+
+func (c *limiterClock) sleep(ctx context.Context, d time.Duration) error {
+	c.mu.Lock()
+	c.delays = append(c.delays, d)
+	c.mu.Unlock()
+	return ctx.Err()
+}
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+	t.Parallel()
+
+	clock := newLimiterClock()
+	limiter := NewRateLimiter(time.Second, 2, WithLimiterClock(clock.now, clock.sleep))
+
+	require.NoError(t, limiter.Wait(context.Background()))
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.Empty(t, clock.delays, "burst requests do not wait")
+
+	state := limiter.State()
+	assert.Equal(t, 0.0, state.Tokens)
+	assert.Equal(t, time.Second, state.Wait)
+	assert.True(t, state.Limited())
+
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.Equal(t, []time.Duration{time.Second}, clock.delays)
+
+	clock.advance(10 * time.Second)
+	state = limiter.State()
+	assert.Equal(t, 2.0, state.Tokens, "bucket does not overflow")
+	assert.False(t, state.Limited())
+}
+
+func TestRateLimiterQueue(t *testing.T) {
+	t.Parallel()
+
+	clock := newLimiterClock()
+	limiter := NewRateLimiter(time.Second, 1, WithLimiterClock(clock.now, clock.sleep))
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	// первая горутина идет сразу, остальные встают в очередь друг за другом
+	assert.ElementsMatch(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, clock.delays)
+	assert.Equal(t, -3.0, limiter.State().Tokens)
+}
+
+func TestRateLimiterPause(t *testing.T) {
+	t.Parallel()
+
+	clock := newLimiterClock()
+	limiter := NewRateLimiter(time.Second, 5, WithLimiterClock(clock.now, clock.sleep))
+
+	limiter.Pause(3 * time.Second)
+	limiter.Pause(time.Second)
+	assert.Equal(t, clock.now().Add(3*time.Second), limiter.State().PausedUntil, "shorter pause keeps the longer one")
+
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.Equal(t, []time.Duration{3 * time.Second}, clock.delays)
+
+	clock.advance(3 * time.Second)
+	state := limiter.State()
+	assert.True(t, state.PausedUntil.IsZero())
+	assert.Equal(t, time.Second, state.Wait, "bucket refills from the end of the pause")
+
+	clock.advance(time.Second)
+	assert.False(t, limiter.State().Limited())
+}
+
+func TestRateLimiterPauseSpacesWaiters(t *testing.T) {
+	t.Parallel()
+
+	clock := newLimiterClock()
+	limiter := NewRateLimiter(time.Second, 5, WithLimiterClock(clock.now, clock.sleep))
+	limiter.Pause(3 * time.Second)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	// после паузы запросы идут по одному в интервал, а не все разом
+	assert.ElementsMatch(t, []time.Duration{3 * time.Second, 4 * time.Second, 5 * time.Second, 6 * time.Second}, clock.delays)
+}
+
+func TestRateLimiterDeadline(t *testing.T) {
+	t.Parallel()
+
+	clock := newLimiterClock()
+	limiter := NewRateLimiter(time.Second, 1, WithLimiterClock(clock.now, clock.sleep))
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithDeadline(context.Background(), clock.now().Add(500*time.Millisecond))
+	defer cancel()
+
+	err := limiter.Wait(ctx)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Empty(t, clock.delays)
+	assert.Equal(t, 0.0, limiter.State().Tokens, "failed wait does not take a token")
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.Wait(cancelled), context.Canceled)
+	assert.Equal(t, 0.0, limiter.State().Tokens, "cancelled wait returns the token")
+}
+
+func TestWttrInProviderRateLimit(t *testing.T) {
+	t.Parallel()
+
+	clock := newLimiterClock()
+	limiter := NewRateLimiter(time.Second, 5, WithLimiterClock(clock.now, clock.sleep))
+	provider, _, sleeper := newTestProvider(t, []func(http.ResponseWriter){
+		respond(http.StatusTooManyRequests, "", "Retry-After", "2"),
+		respond(http.StatusOK, moscowJSON),
+	}, WithRateLimiter(limiter))
+
+	service := NewWeatherService(NewCachingProvider(provider, time.Minute))
+	state, ok := service.RateLimit()
+	require.True(t, ok)
+	assert.False(t, state.Limited())
+
+	_, err := service.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{2 * time.Second}, sleeper.delays, "retry waits for Retry-After")
+	assert.Equal(t, []time.Duration{2 * time.Second}, clock.delays, "limiter holds requests until the pause ends")
+
+	state, ok = service.RateLimit()
+	require.True(t, ok)
+	assert.Equal(t, clock.now().Add(2*time.Second), state.PausedUntil, "other callers see the pause")
+	assert.Equal(t, 0.0, state.Tokens, "after the pause the retry took the only token left")
+
+	_, ok = NewWeatherService(NewWttrInProvider(WithRateLimiter(nil))).RateLimit()
+	assert.False(t, ok, "nil opts out of rate limiting")
+
+	first, second := NewWttrInProvider(), NewWttrInProvider()
+	require.NotNil(t, first.limiter, "rate limiting is on by default")
+	assert.Same(t, first.limiter, second.limiter, "providers share the default limiter")
+}
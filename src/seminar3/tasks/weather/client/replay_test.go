@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/cassette"
+)
+
+// replayProvider направляет провайдер wttr.in на кассету testdata/cassettes/name.
+// С WEATHER_CASSETTE=record запросы уходят в настоящий wttr.in, а кассета перезаписывается.
+func replayProvider(t *testing.T, name string, options ...Option) (*WttrInProvider, *recordingSleeper) {
+	t.Helper()
+
+	mode, err := cassette.ParseMode(os.Getenv(cassette.EnvMode))
+	require.NoError(t, err)
+
+	transport, err := cassette.New(filepath.Join("testdata", "cassettes", name), cassette.WithMode(mode))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, transport.Save())
+	})
+
+	sleeper := &recordingSleeper{}
+	options = append([]Option{WithTransport(transport), WithSleeper(sleeper.sleep), WithRateLimiter(nil)}, options...)
+	provider := NewWttrInProvider(options...)
+	provider.random = func() float64 { return 0.5 }
+
+	return provider, sleeper
+}
+
+func TestWttrInProviderReplay(t *testing.T) {
+	t.Parallel()
+
+	provider, sleeper := replayProvider(t, "wttrin_moscow.json")
+
+	data, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "Moscow", data.City)
+	assert.Equal(t, 7.0, data.Temperature)
+	assert.Equal(t, 4.0, data.FeelsLike)
+	assert.Equal(t, 81, data.Humidity)
+	assert.Equal(t, "Overcast", data.Description)
+	assert.Equal(t, "SSW", data.WindDirection)
+	assert.Equal(t, "2025-10-05T12:05:00+03:00", data.ObservedAt.Format(time.RFC3339))
+
+	forecast, err := provider.GetForecast(context.Background(), "Moscow", 3)
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 3)
+	assert.Equal(t, 3.0, forecast.Days[0].MinTemp)
+	assert.Equal(t, 9.0, forecast.Days[0].MaxTemp)
+	assert.Empty(t, sleeper.delays)
+
+	_, err = provider.GetWeather(context.Background(), "Moscow")
+	assert.ErrorIs(t, err, cassette.ErrNoInteraction, "each recorded response is replayed once")
+}
+
+func TestWttrInProviderReplayRetries(t *testing.T) {
+	t.Parallel()
+
+	// 503, затем 429 с Retry-After: 3, затем ответ
+	provider, sleeper := replayProvider(t, "wttrin_retries.json")
+
+	data, err := provider.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 7.0, data.Temperature)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 3 * time.Second}, sleeper.delays)
+}
+
+func TestWttrInProviderReplayUnknownCity(t *testing.T) {
+	t.Parallel()
+
+	provider, sleeper := replayProvider(t, "wttrin_unknown.json")
+
+	_, err := provider.GetWeather(context.Background(), "Atlantis")
+	assert.ErrorIs(t, err, ErrCityNotFound)
+	assert.Empty(t, sleeper.delays, "404 is not retried")
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// RetryPolicy описывает повторные попытки: экспоненциальная задержка,
+// full jitter и учет заголовка Retry-After
+type RetryPolicy struct {
+	MaxAttempts       int           // общее число попыток, включая первую
+	BaseDelay         time.Duration // задержка перед второй попыткой
+	MaxDelay          time.Duration // верхняя граница задержки
+	Multiplier        float64       // во сколько раз растет задержка
+	Jitter            bool          // случайная задержка в [0, backoff], чтобы клиенты не били в унисон
+	RespectRetryAfter bool          // ждать не меньше, чем просит сервер
+}
+
+// DefaultRetryPolicy 4 попытки с задержкой 1s, 2s, 4s (со случайным разбросом)
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       4,
+		BaseDelay:         time.Second,
+		MaxDelay:          30 * time.Second,
+		Multiplier:        2,
+		Jitter:            true,
+		RespectRetryAfter: true,
+	}
+}
+
+// NoRetry политика с единственной попыткой
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// backoff задержка после failed-й неудачной попытки без учета jitter
+func (p RetryPolicy) backoff(failed int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(multiplier, float64(failed-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// delay вычисляет паузу перед следующей попыткой.
+// false означает, что сервер просит ждать дольше MaxDelay и повторять нет смысла.
+func (p RetryPolicy) delay(failed int, retryAfter time.Duration, random func() float64) (time.Duration, bool) {
+	delay := p.backoff(failed)
+	if p.Jitter {
+		delay = time.Duration(random() * float64(delay))
+	}
+
+	if p.RespectRetryAfter && retryAfter > 0 {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return 0, false
+		}
+		delay = max(delay, retryAfter)
+	}
+
+	return delay, true
+}
+
+// Sleeper ждет d или отмены контекста; подменяется в тестах
+type Sleeper func(ctx context.Context, d time.Duration) error
+
+// Option настраивает WttrInProvider
+type Option func(*WttrInProvider)
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(w *WttrInProvider) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		w.retryPolicy = policy
+	}
+}
+
+func WithSleeper(sleeper Sleeper) Option {
+	return func(w *WttrInProvider) {
+		w.sleep = sleeper
+	}
+}
+
+// statusError ответ сервера с кодом, отличным от 200
+type statusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("сервер вернул ошибку: %s", e.Status)
+}
+
+func (e *statusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode >= 500
+}
+
+// isRetryable классифицирует ошибку попытки
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// ошибки в запросе не исправятся повтором и не говорят о сбое провайдера
+	if errors.Is(err, ErrCityNotFound) || errors.Is(err, ErrUnsupportedLocation) || errors.Is(err, domain.ErrInvalidLocation) {
+		return false
+	}
+
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return false
+	}
+
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.retryable()
+	}
+
+	// сетевые ошибки и обрывы соединения считаем временными
+	return true
+}
+
+func retryAfterOf(err error) time.Duration {
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter разбирает Retry-After: число секунд или HTTP-дата
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+func defaultRandom() float64 {
+	return rand.Float64()
+}
@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const moscowJSON = `{
+	"current_condition": [{
+		"temp_C": "5", "humidity": "80", "windspeedKmph": "10", "FeelsLikeC": "2",
+		"weatherDesc": [{"value": "Cloudy"}]
+	}],
+	"nearest_area": [{"areaName": [{"value": "Moscow"}]}]
+}`
+
+// recordingSleeper запоминает запрошенные задержки и не ждет
+type recordingSleeper struct {
+	delays []time.Duration
+}
+
+func (s *recordingSleeper) sleep(ctx context.Context, d time.Duration) error {
+	s.delays = append(s.delays, d)
+	return ctx.Err()
+}
+
+// newTestProvider направляет провайдер на тестовый сервер, отвечающий ответами responses по очереди
+func newTestProvider(t *testing.T, responses []func(http.ResponseWriter), options ...Option) (*WttrInProvider, *int32, *recordingSleeper) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&calls, 1)) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		responses[i](w)
+	}))
+	t.Cleanup(server.Close)
+
+	// ограничитель частоты проверяется отдельно в ratelimit_test.go
+	sleeper := &recordingSleeper{}
+	options = append([]Option{WithSleeper(sleeper.sleep), WithRateLimiter(nil)}, options...)
+	provider := NewWttrInProvider(options...)
+	provider.baseURL = server.URL + "/%s?format=j1"
+	provider.random = func() float64 { return 0.5 }
+
+	return provider, &calls, sleeper
+}
+
+func respond(status int, body string, headers ...string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2}
+
+	tests := []struct {
+		failed   int
+		expected time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		delay, ok := policy.delay(tt.failed, 0, nil)
+		assert.True(t, ok)
+		assert.Equal(t, tt.expected, delay, "failed attempts: %d", tt.failed)
+	}
+}
+
+func TestRetryPolicyJitterAndRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{BaseDelay: 4 * time.Second, MaxDelay: 10 * time.Second, Multiplier: 2, Jitter: true, RespectRetryAfter: true}
+
+	delay, ok := policy.delay(1, 0, func() float64 { return 0.25 })
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, delay, "full jitter scales backoff")
+
+	delay, ok = policy.delay(1, 7*time.Second, func() float64 { return 0.25 })
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, delay, "Retry-After wins over shorter backoff")
+
+	_, ok = policy.delay(1, time.Minute, func() float64 { return 0.25 })
+	assert.False(t, ok, "Retry-After longer than MaxDelay stops retries")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 5*time.Second, parseRetryAfter("5", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Wed, 01 Jan 2025 12:00:30 GMT", now))
+	assert.Zero(t, parseRetryAfter("", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+}
+
+func TestGetWeatherRetries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		responses     []func(http.ResponseWriter)
+		expectError   bool
+		expectedCalls int32
+		expectedDelay []time.Duration
+	}{
+		{
+			name:          "success on first attempt",
+			responses:     []func(http.ResponseWriter){respond(http.StatusOK, moscowJSON)},
+			expectedCalls: 1,
+		},
+		{
+			name: "retries server errors",
+			responses: []func(http.ResponseWriter){
+				respond(http.StatusServiceUnavailable, ""),
+				respond(http.StatusBadGateway, ""),
+				respond(http.StatusOK, moscowJSON),
+			},
+			expectedCalls: 3,
+			expectedDelay: []time.Duration{500 * time.Millisecond, time.Second},
+		},
+		{
+			name:          "does not retry 404",
+			responses:     []func(http.ResponseWriter){respond(http.StatusNotFound, "")},
+			expectError:   true,
+			expectedCalls: 1,
+		},
+		{
+			name:          "does not retry broken JSON",
+			responses:     []func(http.ResponseWriter){respond(http.StatusOK, "{not json")},
+			expectError:   true,
+			expectedCalls: 1,
+		},
+		{
+			name: "respects Retry-After on 429",
+			responses: []func(http.ResponseWriter){
+				respond(http.StatusTooManyRequests, "", "Retry-After", "3"),
+				respond(http.StatusOK, moscowJSON),
+			},
+			expectedCalls: 2,
+			expectedDelay: []time.Duration{3 * time.Second},
+		},
+		{
+			name:          "gives up after max attempts",
+			responses:     []func(http.ResponseWriter){respond(http.StatusInternalServerError, "")},
+			expectError:   true,
+			expectedCalls: 4,
+			expectedDelay: []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, calls, sleeper := newTestProvider(t, tt.responses)
+			data, err := provider.GetWeather(context.Background(), "Moscow")
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "Moscow", data.City)
+				assert.Equal(t, 5.0, data.Temperature)
+			}
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(calls))
+			assert.Equal(t, tt.expectedDelay, sleeper.delays)
+		})
+	}
+}
+
+func TestGetWeatherCustomPolicy(t *testing.T) {
+	t.Parallel()
+
+	provider, calls, _ := newTestProvider(t,
+		[]func(http.ResponseWriter){respond(http.StatusInternalServerError, "")},
+		WithRetryPolicy(NoRetry()),
+	)
+
+	_, err := provider.GetWeather(context.Background(), "Moscow")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestGetWeatherCancelledDuringWait(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, calls, _ := newTestProvider(t,
+		[]func(http.ResponseWriter){respond(http.StatusServiceUnavailable, "")},
+		WithSleeper(func(ctx context.Context, d time.Duration) error {
+			cancel()
+			return ctx.Err()
+		}),
+	)
+
+	_, err := provider.GetWeather(ctx, "Moscow")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
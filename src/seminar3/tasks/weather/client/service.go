@@ -1,23 +1,243 @@
 package client
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	"example/src/seminar3/tasks/weather/domain"
 )
 
 // WeatherProvider интерфейс для получения погоды
 type WeatherProvider interface {
+	GetWeather(ctx context.Context, city string) (*domain.WeatherData, error)
+}
+
+// ForecastProvider интерфейс для получения прогноза на несколько дней
+type ForecastProvider interface {
+	GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error)
+}
+
+// LegacyWeatherProvider прежний интерфейс провайдера без контекста
+type LegacyWeatherProvider interface {
 	GetWeather(city string) (*domain.WeatherData, error)
 }
 
+// FromLegacy адаптирует провайдер без контекста к WeatherProvider.
+// Прервать уже начатый вызов нельзя, контекст проверяется только перед ним.
+func FromLegacy(provider LegacyWeatherProvider) WeatherProvider {
+	return legacyAdapter{provider: provider}
+}
+
+type legacyAdapter struct {
+	provider LegacyWeatherProvider
+}
+
+func (a legacyAdapter) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.provider.GetWeather(city)
+}
+
+// LegacyAdapter дает прежний вызов GetWeather(city) поверх WeatherProvider,
+// чтобы старый код продолжал работать: запрос идет с context.Background().
+//
+// Deprecated: передавайте контекст в WeatherProvider.GetWeather.
+type LegacyAdapter struct {
+	Provider WeatherProvider
+}
+
+// ToLegacy оборачивает провайдер для кода, который вызывает GetWeather(city)
+//
+// Deprecated: передавайте контекст в WeatherProvider.GetWeather.
+func ToLegacy(provider WeatherProvider) LegacyAdapter {
+	return LegacyAdapter{Provider: provider}
+}
+
+func (a LegacyAdapter) GetWeather(city string) (*domain.WeatherData, error) {
+	return a.Provider.GetWeather(context.Background(), city)
+}
+
+const (
+	defaultBatchConcurrency = 8
+	defaultBatchTimeout     = time.Minute
+)
+
+// Recorder получает каждый успешный ответ сервиса, например чтобы сохранить историю.
+// Ошибки записи не должны мешать ответу, поэтому Recorder сообщает о них сам.
+// Вызывается из нескольких горутин одновременно.
+type Recorder interface {
+	Record(data *domain.WeatherData)
+}
+
 // WeatherService основной сервис
 type WeatherService struct {
-	provider WeatherProvider
+	provider     WeatherProvider
+	recorder     Recorder
+	concurrency  int
+	batchTimeout time.Duration
+}
+
+// ServiceOption настраивает WeatherService
+type ServiceOption func(*WeatherService)
+
+// WithConcurrency ограничивает число одновременных запросов в GetWeatherBatch
+func WithConcurrency(n int) ServiceOption {
+	return func(w *WeatherService) {
+		w.concurrency = max(n, 1)
+	}
+}
+
+// WithBatchTimeout общий срок на весь GetWeatherBatch; 0 - без ограничения
+func WithBatchTimeout(timeout time.Duration) ServiceOption {
+	return func(w *WeatherService) {
+		w.batchTimeout = timeout
+	}
 }
 
-func NewWeatherService(provider WeatherProvider) *WeatherService {
-	return &WeatherService{provider: provider}
+// WithRecorder передает recorder каждый полученный ответ GetWeather и GetWeatherBatch
+func WithRecorder(recorder Recorder) ServiceOption {
+	return func(w *WeatherService) {
+		w.recorder = recorder
+	}
 }
 
-func (w *WeatherService) GetWeather(city string) (*domain.WeatherData, error) {
-	return w.provider.GetWeather(city)
+func NewWeatherService(provider WeatherProvider, options ...ServiceOption) *WeatherService {
+	w := &WeatherService{
+		provider:     provider,
+		concurrency:  defaultBatchConcurrency,
+		batchTimeout: defaultBatchTimeout,
+	}
+
+	for _, option := range options {
+		option(w)
+	}
+
+	return w
+}
+
+func (w *WeatherService) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	data, err := w.provider.GetWeather(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+	w.record(data)
+	return data, nil
+}
+
+// GetWeatherLegacy прежний вызов без контекста, выполняется с context.Background()
+//
+// Deprecated: используйте GetWeather(ctx, city).
+func (w *WeatherService) GetWeatherLegacy(city string) (*domain.WeatherData, error) {
+	return w.GetWeather(context.Background(), city)
+}
+
+func (w *WeatherService) record(data *domain.WeatherData) {
+	if w.recorder != nil {
+		w.recorder.Record(data)
+	}
+}
+
+// GetForecast возвращает прогноз, если провайдер его поддерживает
+func (w *WeatherService) GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error) {
+	forecaster, ok := w.provider.(ForecastProvider)
+	if !ok {
+		return nil, fmt.Errorf("провайдер погоды не поддерживает прогноз")
+	}
+	return forecaster.GetForecast(ctx, city, days)
+}
+
+// RateLimit состояние ограничителя частоты запросов провайдера, чтобы заранее
+// снизить нагрузку, например увеличить интервал опроса; false - ограничения нет
+func (w *WeatherService) RateLimit() (RateLimitState, bool) {
+	if limited, ok := w.provider.(RateLimited); ok {
+		return limited.RateLimit()
+	}
+	return RateLimitState{}, false
+}
+
+// BatchResult результат по одному городу из GetWeatherBatch: либо Data, либо Err
+type BatchResult struct {
+	City string
+	Data *domain.WeatherData
+	Err  error
+}
+
+// GetWeatherBatch запрашивает погоду для нескольких городов параллельно.
+// Результаты идут в порядке cities; одинаковые с точностью до регистра и пробелов
+// города запрашиваются один раз. Города, не успевшие до общего срока, получают ошибку.
+func (w *WeatherService) GetWeatherBatch(ctx context.Context, cities []string) []BatchResult {
+	if w.batchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.batchTimeout)
+		defer cancel()
+	}
+
+	// keys уникальные ключи в порядке первого появления, first - исходное написание
+	var keys []string
+	first := make(map[string]string, len(cities))
+	for _, city := range cities {
+		key := normalizeCity(city)
+		if _, seen := first[key]; !seen && key != "" {
+			first[key] = city
+			keys = append(keys, key)
+		}
+	}
+
+	jobs := make(chan string)
+	var mu sync.Mutex
+	fetched := make(map[string]BatchResult, len(keys))
+
+	var wg sync.WaitGroup
+	for range min(w.concurrency, len(keys)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				result := w.fetchOne(ctx, first[key])
+				mu.Lock()
+				fetched[key] = result
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, key := range keys {
+		jobs <- key
+	}
+	close(jobs)
+	wg.Wait()
+
+	results := make([]BatchResult, len(cities))
+	for i, city := range cities {
+		key := normalizeCity(city)
+		if key == "" {
+			results[i] = BatchResult{City: city, Err: fmt.Errorf("%w: город не может быть пустым", domain.ErrInvalidLocation)}
+			continue
+		}
+
+		result := fetched[key]
+		result.City = city
+		if result.Data != nil {
+			// у дубликатов свои копии, чтобы изменения одной не затрагивали другую
+			data := *result.Data
+			result.Data = &data
+		}
+		results[i] = result
+	}
+	return results
+}
+
+func (w *WeatherService) fetchOne(ctx context.Context, city string) BatchResult {
+	if err := ctx.Err(); err != nil {
+		return BatchResult{City: city, Err: fmt.Errorf("запрос не выполнен: %w", err)}
+	}
+
+	data, err := w.provider.GetWeather(ctx, city)
+	if err == nil {
+		w.record(data)
+	}
+	return BatchResult{City: city, Data: data, Err: err}
 }
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+func TestGetWeatherBatch(t *testing.T) {
+	t.Parallel()
+
+	provider := newFakeProvider(
+		domain.WeatherData{City: "Moscow", Temperature: 5},
+		domain.WeatherData{City: "London", Temperature: 12},
+	)
+	service := NewWeatherService(provider, WithConcurrency(2))
+
+	results := service.GetWeatherBatch(context.Background(), []string{"Moscow", "London", "Atlantis", "  moscow ", ""})
+	require.Len(t, results, 5)
+
+	assert.Equal(t, "Moscow", results[0].City)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, 5.0, results[0].Data.Temperature)
+
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, 12.0, results[1].Data.Temperature)
+
+	assert.Nil(t, results[2].Data)
+	assert.ErrorContains(t, results[2].Err, "city not found")
+
+	assert.Equal(t, "  moscow ", results[3].City, "duplicate keeps its own spelling")
+	require.NoError(t, results[3].Err)
+	assert.NotSame(t, results[0].Data, results[3].Data)
+
+	assert.ErrorContains(t, results[4].Err, "пустым")
+	assert.ErrorIs(t, results[4].Err, domain.ErrInvalidLocation, "server answers 400, not 502")
+
+	assert.Equal(t, 3, provider.callCount(), "duplicates are fetched once")
+}
+
+func TestGetWeatherBatchConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	var running, peak int32
+	provider := providerFunc(func(ctx context.Context, city string) (*domain.WeatherData, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return &domain.WeatherData{City: city}, nil
+	})
+
+	cities := make([]string, 20)
+	for i := range cities {
+		cities[i] = string(rune('A' + i))
+	}
+
+	results := NewWeatherService(provider, WithConcurrency(3)).GetWeatherBatch(context.Background(), cities)
+	for i, result := range results {
+		require.NoError(t, result.Err)
+		assert.Equal(t, cities[i], result.Data.City)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(3))
+}
+
+func TestGetWeatherBatchDeadline(t *testing.T) {
+	t.Parallel()
+
+	provider := providerFunc(func(ctx context.Context, city string) (*domain.WeatherData, error) {
+		if city == "Slow" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &domain.WeatherData{City: city}, nil
+	})
+	service := NewWeatherService(provider, WithConcurrency(1), WithBatchTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	results := service.GetWeatherBatch(context.Background(), []string{"Fast", "Slow", "Late"})
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, context.DeadlineExceeded)
+	assert.ErrorIs(t, results[2].Err, context.DeadlineExceeded, "cities queued after the deadline are not requested")
+}
+
+// cityRecorder запоминает города из переданных ему ответов
+type cityRecorder struct {
+	mu     sync.Mutex
+	cities []string
+}
+
+func (r *cityRecorder) Record(data *domain.WeatherData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cities = append(r.cities, data.City)
+}
+
+func TestWeatherServiceRecorder(t *testing.T) {
+	t.Parallel()
+
+	provider := newFakeProvider(
+		domain.WeatherData{City: "Moscow", Temperature: 5},
+		domain.WeatherData{City: "London", Temperature: 12},
+	)
+	recorder := &cityRecorder{}
+	service := NewWeatherService(provider, WithRecorder(recorder))
+
+	_, err := service.GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	_, err = service.GetWeather(context.Background(), "Atlantis")
+	require.Error(t, err)
+
+	service.GetWeatherBatch(context.Background(), []string{"London", "Atlantis", "london"})
+
+	assert.Equal(t, []string{"Moscow", "London"}, recorder.cities, "only successful responses, duplicates once")
+}
+
+func TestLegacyWrappers(t *testing.T) {
+	t.Parallel()
+
+	provider := newFakeProvider(domain.WeatherData{City: "Moscow", Temperature: 5})
+
+	var legacy LegacyWeatherProvider = ToLegacy(provider)
+	data, err := legacy.GetWeather("Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, data.Temperature)
+
+	data, err = NewWeatherService(provider).GetWeatherLegacy("Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, data.Temperature)
+
+	// обратный путь: старый провайдер внутри нового сервиса
+	data, err = NewWeatherService(FromLegacy(legacy)).GetWeather(context.Background(), "Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, data.Temperature)
+	assert.Equal(t, 3, provider.callCount())
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = FromLegacy(legacy).GetWeather(cancelled, "Moscow")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, provider.callCount(), "cancelled context stops the call before it starts")
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+var errMissing = errors.New("отсутствует")
+
+// validateCurrent проверяет, что в ответе wttr.in есть все для текущей погоды
+func validateCurrent(response *domain.WttrInResponse) error {
+	if len(response.CurrentCondition) == 0 {
+		return newParseError("current_condition", "", errMissing)
+	}
+
+	condition := response.CurrentCondition[0]
+	required := []struct {
+		field string
+		value string
+	}{
+		{"current_condition[0].temp_C", condition.TempC},
+		{"current_condition[0].humidity", condition.Humidity},
+		{"current_condition[0].windspeedKmph", condition.WindSpeedKmph},
+		{"current_condition[0].FeelsLikeC", condition.FeelsLikeC},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return newParseError(r.field, "", errMissing)
+		}
+	}
+
+	if len(condition.WeatherDesc) == 0 {
+		return newParseError("current_condition[0].weatherDesc", "", errMissing)
+	}
+	return nil
+}
+
+// validateForecast проверяет блок weather ответа wttr.in для прогноза на days дней
+func validateForecast(response *domain.WttrInResponse, days int) error {
+	if len(response.Weather) < days {
+		return newParseError("weather", "", fmt.Errorf("прогноз на %d дн., запрошено %d", len(response.Weather), days))
+	}
+
+	for i, day := range response.Weather[:days] {
+		if day.Date == "" {
+			return newParseError(fmt.Sprintf("weather[%d].date", i), "", errMissing)
+		}
+		for j, hour := range day.Hourly {
+			if hour.Time == "" {
+				return newParseError(fmt.Sprintf("weather[%d].hourly[%d].time", i, j), "", errMissing)
+			}
+		}
+	}
+	return nil
+}
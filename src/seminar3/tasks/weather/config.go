@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/config"
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// defaults значения по умолчанию для справки по флагам
+var defaults = config.Default()
+
+// addConfigFlags добавляет флаг --config и настройки клиента wttr.in;
+// возвращает путь к файлу настроек из флага
+func addConfigFlags(flags *flag.FlagSet) *string {
+	path := flags.String("config", "", "файл настроек YAML или JSON (по умолчанию "+config.EnvFile+" или каталог настроек пользователя)")
+	flags.String("wttr-url", defaults.Wttr.URL, "адрес сервиса wttr.in")
+	flags.Duration("wttr-timeout", defaults.Wttr.Timeout, "срок одного запроса к wttr.in")
+	flags.String("wttr-user-agent", defaults.Wttr.UserAgent, "заголовок User-Agent запросов к wttr.in")
+	flags.Int("wttr-attempts", defaults.Wttr.Attempts, "число попыток запроса к wttr.in, включая первую")
+	flags.Duration("wttr-retry-delay", defaults.Wttr.RetryDelay, "задержка перед второй попыткой")
+	flags.Duration("wttr-retry-max-delay", defaults.Wttr.RetryMaxDelay, "верхняя граница задержки между попытками")
+	flags.Duration("wttr-rate-interval", defaults.Wttr.RateInterval, "не чаще одного запроса к wttr.in в этот период после серии из --wttr-burst (0 - без ограничения)")
+	flags.Int("wttr-burst", defaults.Wttr.Burst, "сколько запросов к wttr.in можно сделать подряд без ожидания")
+	return path
+}
+
+// addSettingFlags добавляет флаги основной команды, которые соответствуют
+// ключам настроек; их же понимает weather config show
+func addSettingFlags(flags *flag.FlagSet) {
+	flags.String("provider", defaults.Provider, "источники данных через запятую по приоритету: wttrin, open-meteo, openweathermap (ключ в OPENWEATHERMAP_API_KEY)")
+	flags.Bool("aggregate", defaults.Aggregate, "опросить все источники параллельно и объединить ответы")
+	flags.Duration("cache-ttl", defaults.CacheTTL, "сколько хранить ответы в кеше на диске (0 - без кеша)")
+	flags.String("units", defaults.Units, "единицы измерения: metric, imperial или si")
+	flags.String("lang", defaults.Lang, "язык вывода: ru или en (по умолчанию из LANG)")
+	flags.String("output", defaults.Output, "формат вывода: text, table, json, yaml, csv, line или template")
+	flags.Int("concurrency", defaults.Concurrency, "сколько городов запрашивать одновременно")
+	flags.Duration("timeout", defaults.Timeout, "общий срок на запрос нескольких городов")
+	flags.Bool("history", defaults.History, "сохранять полученную погоду в историю (см. weather history)")
+	flags.String("history-file", defaults.HistoryFile, "файл истории (по умолчанию в каталоге настроек пользователя)")
+}
+
+// loadConfig собирает настройки из файла, окружения и явно указанных флагов
+// и переключает язык сообщений, если он задан
+func loadConfig(flags *flag.FlagSet, path string) *config.Config {
+	loader := config.NewLoader(config.WithFile(path), config.WithEnviron(os.Environ()), config.WithFlags(flags))
+	cfg, _, err := loader.Load()
+	if err != nil {
+		fatal(err)
+	}
+
+	if cfg.Lang != "" {
+		lang, err := i18n.ParseLang(cfg.Lang)
+		if err != nil {
+			fatal(err)
+		}
+		printer = i18n.NewPrinter(lang)
+	}
+	return cfg
+}
+
+// providerOptions настройки wttr.in из конфигурации; с verbose ход запросов пишется в stderr
+func providerOptions(cfg *config.Config, verbose bool) []client.Option {
+	policy := client.DefaultRetryPolicy()
+	policy.MaxAttempts = cfg.Wttr.Attempts
+	policy.BaseDelay = cfg.Wttr.RetryDelay
+	policy.MaxDelay = cfg.Wttr.RetryMaxDelay
+
+	options := []client.Option{
+		client.WithBaseURL(cfg.Wttr.URL),
+		client.WithTimeout(cfg.Wttr.Timeout),
+		client.WithUserAgent(cfg.Wttr.UserAgent),
+		client.WithRetryPolicy(policy),
+	}
+	// один ограничитель на все провайдеры wttr.in команды; 0 - явный отказ от ограничения
+	var limiter *client.RateLimiter
+	if cfg.Wttr.RateInterval > 0 {
+		limiter = client.NewRateLimiter(cfg.Wttr.RateInterval, cfg.Wttr.Burst)
+	}
+	options = append(options, client.WithRateLimiter(limiter))
+	if verbose {
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		options = append(options, client.WithLogger(logger))
+	}
+	return options
+}
+
+// runConfig работа с настройками: weather config show [--config путь] [флаги].
+// Флаги те же, что у основной команды, и применяются последним слоем,
+// поэтому видно, с какими настройками будет работать запрос.
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] != "show" {
+		fatal(fmt.Errorf("неизвестная команда config: ожидается weather config show"))
+	}
+
+	flags := flag.NewFlagSet("config show", flag.ExitOnError)
+	addSettingFlags(flags)
+	path := addConfigFlags(flags)
+	flags.Parse(args[1:])
+
+	loader := config.NewLoader(config.WithFile(*path), config.WithEnviron(os.Environ()), config.WithFlags(flags))
+	_, settings, err := loader.Load()
+	if err != nil {
+		fatal(err)
+	}
+
+	fmt.Println(printer.T(i18n.ConfigFile, loader.Path()))
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "%s\t%s\t%s\n", printer.T(i18n.ConfigKey), printer.T(i18n.ConfigValue), printer.T(i18n.ConfigSource))
+	for _, setting := range settings {
+		source := string(setting.Source)
+		if setting.Origin != "" {
+			source += " (" + setting.Origin + ")"
+		}
+		fmt.Fprintf(table, "%s\t%q\t%s\n", setting.Key, setting.Value, source)
+	}
+	table.Flush()
+}
@@ -0,0 +1,364 @@
+// Package config собирает настройки CLI из нескольких слоев по возрастанию
+// приоритета: значения по умолчанию, файл настроек, переменные окружения WEATHER_*
+// и флаги командной строки. Для каждого параметра запоминается, откуда он взят.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"example/src/seminar3/tasks/weather/client"
+)
+
+const (
+	// EnvPrefix префикс переменных окружения: WEATHER_CACHE_TTL, WEATHER_WTTR_URL
+	EnvPrefix = "WEATHER_"
+	// EnvFile переменная с путем к файлу настроек
+	EnvFile = "WEATHER_CONFIG"
+)
+
+// Config настройки CLI. Тег key задает имя параметра в файле; из него же
+// получаются имя переменной окружения и флага (см. Env и Flag).
+type Config struct {
+	Provider    string        `key:"provider"`
+	Aggregate   bool          `key:"aggregate"`
+	CacheTTL    time.Duration `key:"cache_ttl"`
+	Units       string        `key:"units"`
+	Lang        string        `key:"lang"`
+	Output      string        `key:"output"`
+	Concurrency int           `key:"concurrency"`
+	Timeout     time.Duration `key:"timeout"`
+	History     bool          `key:"history"`
+	HistoryFile string        `key:"history_file"`
+	Wttr        WttrConfig    `key:"wttr"`
+}
+
+// WttrConfig настройки клиента wttr.in
+type WttrConfig struct {
+	// URL адрес сервиса без пути, например https://wttr.in
+	URL           string        `key:"url"`
+	Timeout       time.Duration `key:"timeout"`
+	UserAgent     string        `key:"user_agent"`
+	Attempts      int           `key:"attempts"`
+	RetryDelay    time.Duration `key:"retry_delay"`
+	RetryMaxDelay time.Duration `key:"retry_max_delay"`
+	// RateInterval и Burst ограничитель частоты: Burst запросов подряд, затем один
+	// в RateInterval; RateInterval 0 явно снимает ограничение
+	RateInterval time.Duration `key:"rate_interval"`
+	Burst        int           `key:"burst"`
+}
+
+// Default настройки, если они нигде не заданы
+func Default() Config {
+	retry := client.DefaultRetryPolicy()
+	return Config{
+		Provider:    "wttrin",
+		CacheTTL:    10 * time.Minute,
+		Units:       "metric",
+		Output:      "text",
+		Concurrency: 8,
+		Timeout:     time.Minute,
+		History:     true,
+		Wttr: WttrConfig{
+			URL:           client.DefaultWttrInURL,
+			Timeout:       client.DefaultTimeout,
+			UserAgent:     client.DefaultUserAgent,
+			Attempts:      retry.MaxAttempts,
+			RetryDelay:    retry.BaseDelay,
+			RetryMaxDelay: retry.MaxDelay,
+			RateInterval:  client.DefaultRateInterval,
+			Burst:         client.DefaultRateBurst,
+		},
+	}
+}
+
+// DefaultPath файл настроек в каталоге настроек пользователя, например ~/.config/weather/config.yaml
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("не удалось определить каталог настроек: %w", err)
+	}
+	return filepath.Join(dir, "weather", "config.yaml"), nil
+}
+
+// Source слой, из которого взято значение
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Setting один параметр итоговой конфигурации
+type Setting struct {
+	Key    string // wttr.user_agent
+	Value  string
+	Source Source
+	Origin string // путь к файлу, имя переменной или флага; пусто для значений по умолчанию
+}
+
+// Env имя переменной окружения для параметра: wttr.user_agent -> WEATHER_WTTR_USER_AGENT
+func Env(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Flag имя флага для параметра: wttr.user_agent -> wttr-user-agent
+func Flag(key string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(key)
+}
+
+// Loader читает слои конфигурации
+type Loader struct {
+	path     string
+	explicit bool // файл указан явно, и его отсутствие - ошибка
+	environ  []string
+	flags    *flag.FlagSet
+}
+
+type Option func(*Loader)
+
+// WithFile файл настроек вместо пути из WEATHER_CONFIG или DefaultPath
+func WithFile(path string) Option {
+	return func(l *Loader) {
+		if path != "" {
+			l.path = path
+			l.explicit = true
+		}
+	}
+}
+
+// WithEnviron переменные окружения в формате os.Environ
+func WithEnviron(environ []string) Option {
+	return func(l *Loader) {
+		l.environ = environ
+	}
+}
+
+// WithFlags разобранные флаги: учитываются только явно указанные и
+// только те, имя которых совпадает с Flag(key) какого-либо параметра
+func WithFlags(flags *flag.FlagSet) Option {
+	return func(l *Loader) {
+		l.flags = flags
+	}
+}
+
+func NewLoader(options ...Option) *Loader {
+	l := &Loader{}
+	for _, option := range options {
+		option(l)
+	}
+	if !l.explicit {
+		if path, _ := lookupEnv(l.environ, EnvFile); path != "" {
+			l.path, l.explicit = path, true
+		} else if path, err := DefaultPath(); err == nil {
+			l.path = path
+		}
+	}
+	return l
+}
+
+// Path файл настроек, который читает Load
+func (l *Loader) Path() string {
+	return l.path
+}
+
+// Load собирает конфигурацию и источники всех параметров в порядке объявления
+func (l *Loader) Load() (*Config, []Setting, error) {
+	cfg := Default()
+	fields := fieldsOf(&cfg)
+	settings := make([]Setting, len(fields))
+	index := make(map[string]int, len(fields))
+	for i, f := range fields {
+		settings[i] = Setting{Key: f.key, Value: f.String(), Source: SourceDefault}
+		index[f.key] = i
+	}
+
+	set := func(key, value string, source Source, origin string) error {
+		i, ok := index[key]
+		if !ok {
+			return fmt.Errorf("неизвестный параметр %q в %s", key, origin)
+		}
+		if err := fields[i].Set(value); err != nil {
+			return fmt.Errorf("параметр %s из %s: %w", key, origin, err)
+		}
+		settings[i] = Setting{Key: key, Value: fields[i].String(), Source: source, Origin: origin}
+		return nil
+	}
+
+	values, err := l.readFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, key := range sortedKeys(values) {
+		if err := set(key, values[key], SourceFile, l.path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, f := range fields {
+		name := Env(f.key)
+		if value, ok := lookupEnv(l.environ, name); ok {
+			if err := set(f.key, value, SourceEnv, name); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if l.flags != nil {
+		byFlag := make(map[string]string, len(fields))
+		for _, f := range fields {
+			byFlag[Flag(f.key)] = f.key
+		}
+
+		var flagErr error
+		l.flags.Visit(func(fl *flag.Flag) {
+			key, ok := byFlag[fl.Name]
+			if ok && flagErr == nil {
+				flagErr = set(key, fl.Value.String(), SourceFlag, "--"+fl.Name)
+			}
+		})
+		if flagErr != nil {
+			return nil, nil, flagErr
+		}
+	}
+
+	return &cfg, settings, nil
+}
+
+// readFile читает файл настроек YAML или JSON в плоский набор "ключ.через.точку" -> значение.
+// Отсутствие файла по умолчанию не ошибка: настраивать что-либо не обязательно.
+func (l *Loader) readFile() (map[string]string, error) {
+	if l.path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, fs.ErrNotExist) && !l.explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл настроек: %w", err)
+	}
+
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("ошибка разбора файла настроек %s: %w", l.path, err)
+	}
+
+	values := make(map[string]string)
+	flatten("", tree, values)
+	return values, nil
+}
+
+func flatten(prefix string, tree map[string]any, values map[string]string) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flatten(key, nested, values)
+			continue
+		}
+		if value == nil {
+			value = ""
+		}
+		values[key] = fmt.Sprint(value)
+	}
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lookupEnv(environ []string, name string) (string, bool) {
+	for _, entry := range environ {
+		if key, value, ok := strings.Cut(entry, "="); ok && key == name {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// field параметр Config, найденный по тегу key
+type field struct {
+	key   string
+	value reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// fieldsOf параметры cfg в порядке объявления, вложенные структуры - через точку
+func fieldsOf(cfg *Config) []field {
+	var fields []field
+	var walk func(prefix string, v reflect.Value)
+	walk = func(prefix string, v reflect.Value) {
+		for i := range v.NumField() {
+			key := v.Type().Field(i).Tag.Get("key")
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			if v.Field(i).Kind() == reflect.Struct {
+				walk(key, v.Field(i))
+				continue
+			}
+			fields = append(fields, field{key: key, value: v.Field(i)})
+		}
+	}
+	walk("", reflect.ValueOf(cfg).Elem())
+	return fields
+}
+
+func (f field) String() string {
+	if f.value.Type() == durationType {
+		return time.Duration(f.value.Int()).String()
+	}
+	return fmt.Sprint(f.value.Interface())
+}
+
+func (f field) Set(value string) error {
+	value = strings.TrimSpace(value)
+
+	switch {
+	case f.value.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("ожидается длительность, например 10m или 30s: %w", err)
+		}
+		f.value.SetInt(int64(d))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(value)
+	case f.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("ожидается true или false: %w", err)
+		}
+		f.value.SetBool(b)
+	case f.value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("ожидается целое число: %w", err)
+		}
+		f.value.SetInt(int64(n))
+	default:
+		return fmt.Errorf("неподдерживаемый тип %s", f.value.Type())
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile создает файл настроек во временном каталоге
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// settingOf параметр key из результата Load
+func settingOf(t *testing.T, settings []Setting, key string) Setting {
+	t.Helper()
+
+	for _, setting := range settings {
+		if setting.Key == key {
+			return setting
+		}
+	}
+	t.Fatalf("no setting %q", key)
+	return Setting{}
+}
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key  string
+		env  string
+		flag string
+	}{
+		{key: "provider", env: "WEATHER_PROVIDER", flag: "provider"},
+		{key: "cache_ttl", env: "WEATHER_CACHE_TTL", flag: "cache-ttl"},
+		{key: "wttr.user_agent", env: "WEATHER_WTTR_USER_AGENT", flag: "wttr-user-agent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.env, Env(tt.key))
+			assert.Equal(t, tt.flag, Flag(tt.key))
+		})
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Parallel()
+
+	loader := NewLoader(WithFile(""), WithEnviron(nil))
+	// каталог настроек пользователя в тестах не читаем
+	loader.path = filepath.Join(t.TempDir(), "missing.yaml")
+
+	cfg, settings, err := loader.Load()
+	require.NoError(t, err)
+	assert.Equal(t, Default(), *cfg)
+	require.Len(t, settings, len(fieldsOf(cfg)))
+	assert.Equal(t, Setting{Key: "wttr.url", Value: "https://wttr.in", Source: SourceDefault}, settingOf(t, settings, "wttr.url"))
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: `provider: open-meteo,wttrin
+cache_ttl: 5m
+history: false
+wttr:
+  url: http://localhost:8080/
+  attempts: 5
+`,
+		},
+		{
+			name:    "json",
+			file:    "config.json",
+			content: `{"provider": "open-meteo,wttrin", "cache_ttl": "5m", "history": false, "wttr": {"url": "http://localhost:8080/", "attempts": 5}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeFile(t, tt.file, tt.content)
+			cfg, settings, err := NewLoader(WithFile(path)).Load()
+			require.NoError(t, err)
+
+			assert.Equal(t, "open-meteo,wttrin", cfg.Provider)
+			assert.Equal(t, 5*time.Minute, cfg.CacheTTL)
+			assert.False(t, cfg.History)
+			assert.Equal(t, "http://localhost:8080/", cfg.Wttr.URL)
+			assert.Equal(t, 5, cfg.Wttr.Attempts)
+			assert.Equal(t, Default().Wttr.Timeout, cfg.Wttr.Timeout, "keys missing in file keep defaults")
+
+			assert.Equal(t, Setting{Key: "wttr.attempts", Value: "5", Source: SourceFile, Origin: path}, settingOf(t, settings, "wttr.attempts"))
+			assert.Equal(t, SourceDefault, settingOf(t, settings, "wttr.timeout").Source)
+		})
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "config.yaml", "units: imperial\nlang: en\nconcurrency: 2\n")
+	environ := []string{"HOME=/root", "WEATHER_LANG=ru", "WEATHER_CONCURRENCY=4", "WEATHER_WTTR_TIMEOUT=3s"}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Int("concurrency", 8, "")
+	flags.String("units", "metric", "")
+	flags.Bool("verbose", false, "")
+	require.NoError(t, flags.Parse([]string{"--concurrency", "16", "--verbose"}))
+
+	cfg, settings, err := NewLoader(WithFile(path), WithEnviron(environ), WithFlags(flags)).Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "imperial", cfg.Units, "flag not set explicitly does not override file")
+	assert.Equal(t, "ru", cfg.Lang, "env overrides file")
+	assert.Equal(t, 16, cfg.Concurrency, "flag overrides env")
+	assert.Equal(t, 3*time.Second, cfg.Wttr.Timeout)
+
+	assert.Equal(t, Setting{Key: "units", Value: "imperial", Source: SourceFile, Origin: path}, settingOf(t, settings, "units"))
+	assert.Equal(t, Setting{Key: "lang", Value: "ru", Source: SourceEnv, Origin: "WEATHER_LANG"}, settingOf(t, settings, "lang"))
+	assert.Equal(t, Setting{Key: "concurrency", Value: "16", Source: SourceFlag, Origin: "--concurrency"}, settingOf(t, settings, "concurrency"))
+}
+
+func TestLoaderPath(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "custom.yaml", "provider: owm\n")
+
+	loader := NewLoader(WithEnviron([]string{EnvFile + "=" + path}))
+	assert.Equal(t, path, loader.Path())
+	cfg, _, err := loader.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "owm", cfg.Provider)
+
+	other := writeFile(t, "other.yaml", "provider: wttrin\n")
+	assert.Equal(t, other, NewLoader(WithFile(other), WithEnviron([]string{EnvFile + "=" + path})).Path(), "flag wins over "+EnvFile)
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+
+	tests := []struct {
+		name     string
+		path     string
+		environ  []string
+		contains string
+	}{
+		{name: "unknown key", path: writeFile(t, "unknown.yaml", "wttr:\n  retries: 3\n"), contains: `неизвестный параметр "wttr.retries"`},
+		{name: "bad duration", path: writeFile(t, "duration.yaml", "cache_ttl: 10\n"), contains: "cache_ttl"},
+		{name: "bad int", path: writeFile(t, "int.yaml", "concurrency: many\n"), contains: "concurrency"},
+		{name: "bad syntax", path: writeFile(t, "syntax.yaml", "provider: [\n"), contains: "ошибка разбора"},
+		{name: "missing explicit file", path: missing, contains: "не удалось прочитать"},
+		{name: "missing file from env", environ: []string{EnvFile + "=" + missing}, contains: "не удалось прочитать"},
+		{name: "bad env", path: writeFile(t, "ok.yaml", ""), environ: []string{"WEATHER_HISTORY=maybe"}, contains: "WEATHER_HISTORY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := NewLoader(WithFile(tt.path), WithEnviron(tt.environ)).Load()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.contains)
+		})
+	}
+}
@@ -1,21 +1,33 @@
 package domain
 
 import (
-	"fmt"
+	"os"
 	"time"
+
+	"example/src/seminar3/tasks/weather/i18n"
 )
 
 type WttrInResponse struct {
 	CurrentCondition []CurrentCondition `json:"current_condition"`
 	NearestArea      []NearestArea      `json:"nearest_area"`
+	Weather          []WeatherDay       `json:"weather"`
 }
 
 type CurrentCondition struct {
-	TempC         string        `json:"temp_C"`
-	Humidity      string        `json:"humidity"`
-	WeatherDesc   []WeatherDesc `json:"weatherDesc"`
-	WindSpeedKmph string        `json:"windspeedKmph"`
-	FeelsLikeC    string        `json:"FeelsLikeC"`
+	TempC            string        `json:"temp_C"`
+	Humidity         string        `json:"humidity"`
+	WeatherDesc      []WeatherDesc `json:"weatherDesc"`
+	WindSpeedKmph    string        `json:"windspeedKmph"`
+	FeelsLikeC       string        `json:"FeelsLikeC"`
+	Pressure         string        `json:"pressure"`   // гПа
+	Visibility       string        `json:"visibility"` // км
+	UVIndex          string        `json:"uvIndex"`
+	CloudCover       string        `json:"cloudcover"` // %
+	PrecipMM         string        `json:"precipMM"`
+	WindDir16Point   string        `json:"winddir16Point"`
+	WindDirDegree    string        `json:"winddirDegree"`
+	ObservationTime  string        `json:"observation_time"` // UTC, например "09:05 AM"
+	LocalObsDateTime string        `json:"localObsDateTime"` // местное время, например "2025-10-05 12:05 PM"
 }
 
 type WeatherDesc struct {
@@ -30,22 +42,33 @@ type AreaName struct {
 	Value string `json:"value"`
 }
 
+// WeatherData текущая погода в метрической системе.
+// Расширенные поля - указатели: nil означает, что провайдер их не прислал,
+// а ноль - настоящее значение (нет осадков, ясное небо).
 type WeatherData struct {
-	City        string  `json:"city"`
-	Temperature float64 `json:"temperature"`
-	Humidity    int     `json:"humidity"`
-	Description string  `json:"description"`
-	WindSpeed   float64 `json:"wind_speed"`
-	FeelsLike   float64 `json:"feels_like"`
-}
-
-// Display отображает погоду в консоли
-func (w *WeatherData) Display() {
-	fmt.Printf("\n🌤️  Погода в %s\n", w.City)
-	fmt.Printf("🌡️  Температура: %.1f°C\n", w.Temperature)
-	fmt.Printf("🤔 Ощущается как: %.1f°C\n", w.FeelsLike)
-	fmt.Printf("💧 Влажность: %d%%\n", w.Humidity)
-	fmt.Printf("💨 Скорость ветра: %.1f км/ч\n", w.WindSpeed)
-	fmt.Printf("📝 Описание: %s\n", w.Description)
-	fmt.Printf("🕒 Время запроса: %s\n", time.Now().Format("15:04:05"))
+	City          string    `json:"city"`
+	Temperature   float64   `json:"temperature"`
+	Humidity      int       `json:"humidity"`
+	Description   string    `json:"description"`
+	WindSpeed     float64   `json:"wind_speed"`
+	FeelsLike     float64   `json:"feels_like"`
+	Pressure      *float64  `json:"pressure,omitempty"`       // гПа
+	Visibility    *float64  `json:"visibility,omitempty"`     // км
+	UVIndex       *int      `json:"uv_index,omitempty"`       // УФ-индекс
+	CloudCover    *int      `json:"cloud_cover,omitempty"`    // облачность, %
+	Precipitation *float64  `json:"precipitation,omitempty"`  // осадки, мм
+	WindDirection string    `json:"wind_direction,omitempty"` // откуда дует ветер, 16 румбов: "SSW"
+	WindDegree    *int      `json:"wind_degree,omitempty"`    // откуда дует ветер, градусы; 0 - север
+	ObservedAt    time.Time `json:"observed_at,omitzero"`     // время наблюдения на станции
+	Sources       []string  `json:"sources,omitempty"`        // какие провайдеры дали данные
+}
+
+// Ptr указатель на копию v, для заполнения расширенных полей WeatherData
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Display отображает погоду в консоли на языке printer в единицах units
+func (w *WeatherData) Display(printer *i18n.Printer, units Units) {
+	TextRenderer{Printer: printer, Units: units}.Render(os.Stdout, w)
 }
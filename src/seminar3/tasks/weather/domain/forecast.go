@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+type WeatherDay struct {
+	Date      string       `json:"date"`
+	MaxTempC  string       `json:"maxtempC"`
+	MinTempC  string       `json:"mintempC"`
+	AvgTempC  string       `json:"avgtempC"`
+	Astronomy []Astronomy  `json:"astronomy"`
+	Hourly    []HourlyData `json:"hourly"`
+}
+
+type Astronomy struct {
+	Sunrise string `json:"sunrise"`
+	Sunset  string `json:"sunset"`
+}
+
+type HourlyData struct {
+	Time          string        `json:"time"`
+	TempC         string        `json:"tempC"`
+	FeelsLikeC    string        `json:"FeelsLikeC"`
+	Humidity      string        `json:"humidity"`
+	WindSpeedKmph string        `json:"windspeedKmph"`
+	ChanceOfRain  string        `json:"chanceofrain"`
+	WeatherDesc   []WeatherDesc `json:"weatherDesc"`
+}
+
+type Forecast struct {
+	City string          `json:"city"`
+	Days []DailyForecast `json:"days"`
+}
+
+type DailyForecast struct {
+	Date    time.Time        `json:"date"`
+	MinTemp float64          `json:"min_temp"`
+	MaxTemp float64          `json:"max_temp"`
+	AvgTemp float64          `json:"avg_temp"`
+	Sunrise string           `json:"sunrise"`
+	Sunset  string           `json:"sunset"`
+	Hourly  []HourlyForecast `json:"hourly"`
+}
+
+type HourlyForecast struct {
+	Time         time.Time `json:"time"`
+	Temperature  float64   `json:"temperature"`
+	FeelsLike    float64   `json:"feels_like"`
+	Humidity     int       `json:"humidity"`
+	WindSpeed    float64   `json:"wind_speed"`
+	ChanceOfRain int       `json:"chance_of_rain"`
+	Description  string    `json:"description"`
+}
+
+// ForecastRenderer выводит прогноз; его реализуют рендереры text, json, yaml и csv
+type ForecastRenderer interface {
+	RenderForecast(w io.Writer, f *Forecast) error
+}
+
+// Display отображает прогноз в консоли на языке printer в единицах units
+func (f *Forecast) Display(printer *i18n.Printer, units Units) {
+	_ = TextRenderer{Printer: printer, Units: units}.RenderForecast(os.Stdout, f)
+}
+
+func (r TextRenderer) RenderForecast(w io.Writer, f *Forecast) error {
+	p, units := r.Printer, r.Units
+	var out strings.Builder
+	fmt.Fprintf(&out, "\n%s\n", p.T(i18n.ForecastTitle, f.City))
+	for _, day := range f.Days {
+		fmt.Fprintf(&out, "\n%s\n", day.Date.Format(p.T(i18n.ForecastDateLayout)))
+		fmt.Fprintln(&out, p.T(i18n.ForecastTemp,
+			formatTemperature(day.MinTemp, units),
+			formatTemperature(day.MaxTemp, units),
+			formatTemperature(day.AvgTemp, units)))
+		fmt.Fprintln(&out, p.T(i18n.ForecastSun, day.Sunrise, day.Sunset))
+		for _, hour := range day.Hourly {
+			fmt.Fprintf(&out, "   %s  %5.1f%s  💧%3d%%  💨%4.1f %s  ☔%3d%%  %s\n",
+				hour.Time.Format("15:04"), units.Temperature(hour.Temperature), units.TemperatureSymbol(),
+				hour.Humidity, units.Speed(hour.WindSpeed), p.T(units.SpeedUnit()),
+				hour.ChanceOfRain, hour.Description)
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// ForecastRecord прогноз в выбранных единицах для машиночитаемых форматов
+type ForecastRecord struct {
+	City  string              `json:"city" yaml:"city"`
+	Days  []ForecastDayRecord `json:"days" yaml:"days"`
+	Units Units               `json:"units" yaml:"units"`
+}
+
+type ForecastDayRecord struct {
+	Date    string               `json:"date" yaml:"date"`
+	MinTemp float64              `json:"min_temp" yaml:"min_temp"`
+	MaxTemp float64              `json:"max_temp" yaml:"max_temp"`
+	AvgTemp float64              `json:"avg_temp" yaml:"avg_temp"`
+	Sunrise string               `json:"sunrise" yaml:"sunrise"`
+	Sunset  string               `json:"sunset" yaml:"sunset"`
+	Hourly  []ForecastHourRecord `json:"hourly" yaml:"hourly"`
+}
+
+type ForecastHourRecord struct {
+	Time         string  `json:"time" yaml:"time"`
+	Temperature  float64 `json:"temperature" yaml:"temperature"`
+	FeelsLike    float64 `json:"feels_like" yaml:"feels_like"`
+	Humidity     int     `json:"humidity" yaml:"humidity"`
+	WindSpeed    float64 `json:"wind_speed" yaml:"wind_speed"`
+	ChanceOfRain int     `json:"chance_of_rain" yaml:"chance_of_rain"`
+	Description  string  `json:"description" yaml:"description"`
+}
+
+// NewForecastRecord переводит прогноз в units; дата в формате 2006-01-02, время 15:04
+func NewForecastRecord(f *Forecast, units Units) ForecastRecord {
+	record := ForecastRecord{City: f.City, Days: make([]ForecastDayRecord, len(f.Days)), Units: units}
+	for i, day := range f.Days {
+		dayRecord := ForecastDayRecord{
+			Date:    day.Date.Format(time.DateOnly),
+			MinTemp: round1(units.Temperature(day.MinTemp)),
+			MaxTemp: round1(units.Temperature(day.MaxTemp)),
+			AvgTemp: round1(units.Temperature(day.AvgTemp)),
+			Sunrise: day.Sunrise,
+			Sunset:  day.Sunset,
+			Hourly:  make([]ForecastHourRecord, len(day.Hourly)),
+		}
+		for j, hour := range day.Hourly {
+			dayRecord.Hourly[j] = ForecastHourRecord{
+				Time:         hour.Time.Format("15:04"),
+				Temperature:  round1(units.Temperature(hour.Temperature)),
+				FeelsLike:    round1(units.Temperature(hour.FeelsLike)),
+				Humidity:     hour.Humidity,
+				WindSpeed:    round1(units.Speed(hour.WindSpeed)),
+				ChanceOfRain: hour.ChanceOfRain,
+				Description:  hour.Description,
+			}
+		}
+		record.Days[i] = dayRecord
+	}
+	return record
+}
+
+// RenderForecast выводит прогноз одним объектом: прогноз всегда для одного города
+func (r JSONRenderer) RenderForecast(w io.Writer, f *Forecast) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewForecastRecord(f, r.Units))
+}
+
+func (r YAMLRenderer) RenderForecast(w io.Writer, f *Forecast) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	return encoder.Encode(NewForecastRecord(f, r.Units))
+}
+
+var forecastCSVHeader = []string{
+	"city", "date", "min_temp", "max_temp", "avg_temp", "sunrise", "sunset",
+	"time", "temperature", "feels_like", "humidity", "wind_speed", "chance_of_rain", "description", "units",
+}
+
+// RenderForecast выводит строку на каждый час; поля дня повторяются, день без
+// почасовых данных выводится одной строкой с пустыми часовыми колонками
+func (r CSVRenderer) RenderForecast(w io.Writer, f *Forecast) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(forecastCSVHeader); err != nil {
+		return err
+	}
+
+	record := NewForecastRecord(f, r.Units)
+	for _, day := range record.Days {
+		dayColumns := []string{
+			record.City,
+			day.Date,
+			strconv.FormatFloat(day.MinTemp, 'f', 1, 64),
+			strconv.FormatFloat(day.MaxTemp, 'f', 1, 64),
+			strconv.FormatFloat(day.AvgTemp, 'f', 1, 64),
+			day.Sunrise,
+			day.Sunset,
+		}
+		if len(day.Hourly) == 0 {
+			row := append(slices.Clone(dayColumns), "", "", "", "", "", "", "", string(record.Units))
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, hour := range day.Hourly {
+			row := append(slices.Clone(dayColumns),
+				hour.Time,
+				strconv.FormatFloat(hour.Temperature, 'f', 1, 64),
+				strconv.FormatFloat(hour.FeelsLike, 'f', 1, 64),
+				strconv.Itoa(hour.Humidity),
+				strconv.FormatFloat(hour.WindSpeed, 'f', 1, 64),
+				strconv.Itoa(hour.ChanceOfRain),
+				hour.Description,
+				string(record.Units),
+			)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidLocation строку нельзя разобрать как местоположение
+var ErrInvalidLocation = errors.New("некорректное местоположение")
+
+// LocationKind способ задать местоположение
+type LocationKind int
+
+const (
+	LocationCity        LocationKind = iota // название города: "New York", "Москва"
+	LocationCoordinates                     // широта и долгота: "55.75,37.62"
+	LocationAirport                         // код аэропорта IATA: "airport:SVO"
+	LocationAuto                            // по IP-адресу запроса: "auto"
+)
+
+func (k LocationKind) String() string {
+	switch k {
+	case LocationCity:
+		return "city"
+	case LocationCoordinates:
+		return "coordinates"
+	case LocationAirport:
+		return "airport"
+	case LocationAuto:
+		return "auto"
+	default:
+		return "unknown"
+	}
+}
+
+// Location местоположение, для которого запрашивается погода
+type Location struct {
+	Kind      LocationKind
+	Name      string  // для LocationCity
+	Latitude  float64 // для LocationCoordinates
+	Longitude float64 // для LocationCoordinates
+	Code      string  // для LocationAirport, в верхнем регистре
+}
+
+var (
+	coordinatesPattern = regexp.MustCompile(`^([-+]?\d+(?:\.\d+)?)\s*,\s*([-+]?\d+(?:\.\d+)?)$`)
+	airportPattern     = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// ParseLocation разбирает строку местоположения:
+//
+//	"auto"        - по IP-адресу
+//	"55.75,37.62" - координаты
+//	"airport:svo" - код аэропорта (три латинские буквы)
+//	остальное     - название города
+//
+// Код аэропорта нужно задавать с префиксом: без него "NYC", "UFA" или "SPB"
+// неотличимы от коротких названий городов и остаются городами.
+func ParseLocation(value string) (Location, error) {
+	value = strings.Join(strings.Fields(value), " ")
+
+	switch {
+	case value == "":
+		return Location{}, fmt.Errorf("%w: пустая строка", ErrInvalidLocation)
+	case strings.EqualFold(value, "auto"):
+		return Location{Kind: LocationAuto}, nil
+	}
+
+	if match := coordinatesPattern.FindStringSubmatch(value); match != nil {
+		latitude, err := parseCoordinate(match[1])
+		if err != nil {
+			return Location{}, err
+		}
+		longitude, err := parseCoordinate(match[2])
+		if err != nil {
+			return Location{}, err
+		}
+		return NewCoordinates(latitude, longitude)
+	}
+
+	if code, ok := strings.CutPrefix(strings.ToLower(value), "airport:"); ok {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !airportPattern.MatchString(code) {
+			return Location{}, fmt.Errorf("%w: код аэропорта %q должен состоять из трех латинских букв", ErrInvalidLocation, code)
+		}
+		return Location{Kind: LocationAirport, Code: code}, nil
+	}
+
+	return Location{Kind: LocationCity, Name: value}, nil
+}
+
+// NewCoordinates проверяет диапазоны широты и долготы
+func NewCoordinates(latitude, longitude float64) (Location, error) {
+	if latitude < -90 || latitude > 90 {
+		return Location{}, fmt.Errorf("%w: широта %v вне диапазона [-90, 90]", ErrInvalidLocation, latitude)
+	}
+	if longitude < -180 || longitude > 180 {
+		return Location{}, fmt.Errorf("%w: долгота %v вне диапазона [-180, 180]", ErrInvalidLocation, longitude)
+	}
+	return Location{Kind: LocationCoordinates, Latitude: latitude, Longitude: longitude}, nil
+}
+
+// String каноническая запись, которую снова понимает ParseLocation
+func (l Location) String() string {
+	switch l.Kind {
+	case LocationCoordinates:
+		return strconv.FormatFloat(l.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(l.Longitude, 'f', -1, 64)
+	case LocationAirport:
+		return "airport:" + l.Code
+	case LocationAuto:
+		return "auto"
+	default:
+		return l.Name
+	}
+}
+
+// parseCoordinate разбирает число, уже проверенное регулярным выражением;
+// ошибка возможна, только если оно не помещается в float64
+func parseCoordinate(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: координата %.20q вне диапазона", ErrInvalidLocation, s)
+	}
+	return v, nil
+}
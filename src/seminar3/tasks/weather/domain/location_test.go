@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected Location
+		str      string
+	}{
+		{"Moscow", Location{Kind: LocationCity, Name: "Moscow"}, "Moscow"},
+		{"  New   York ", Location{Kind: LocationCity, Name: "New York"}, "New York"},
+		{"Санкт-Петербург", Location{Kind: LocationCity, Name: "Санкт-Петербург"}, "Санкт-Петербург"},
+		{"Ufa", Location{Kind: LocationCity, Name: "Ufa"}, "Ufa"},
+		{"airport:SVO", Location{Kind: LocationAirport, Code: "SVO"}, "airport:SVO"},
+		{"Airport: jfk", Location{Kind: LocationAirport, Code: "JFK"}, "airport:JFK"},
+		// три заглавные буквы без префикса - это город, а не код IATA
+		{"SVO", Location{Kind: LocationCity, Name: "SVO"}, "SVO"},
+		{"NYC", Location{Kind: LocationCity, Name: "NYC"}, "NYC"},
+		{"UFA", Location{Kind: LocationCity, Name: "UFA"}, "UFA"},
+		{"SPB", Location{Kind: LocationCity, Name: "SPB"}, "SPB"},
+		{"55.75,37.62", Location{Kind: LocationCoordinates, Latitude: 55.75, Longitude: 37.62}, "55.75,37.62"},
+		{"-33.87, +151.21", Location{Kind: LocationCoordinates, Latitude: -33.87, Longitude: 151.21}, "-33.87,151.21"},
+		{"auto", Location{Kind: LocationAuto}, "auto"},
+		{"AUTO", Location{Kind: LocationAuto}, "auto"},
+	}
+
+	for _, tt := range tests {
+		location, err := ParseLocation(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, location, tt.input)
+		assert.Equal(t, tt.str, location.String(), tt.input)
+
+		reparsed, err := ParseLocation(location.String())
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, location, reparsed, "String() round-trips for %q", tt.input)
+	}
+}
+
+func TestParseLocationErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "   ", "91,0", "0,181", "airport:sheremetyevo", "airport:", "1" + strings.Repeat("0", 400) + ",0"} {
+		_, err := ParseLocation(input)
+		assert.ErrorIs(t, err, ErrInvalidLocation, input)
+	}
+}
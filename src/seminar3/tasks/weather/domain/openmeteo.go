@@ -0,0 +1,35 @@
+package domain
+
+type OpenMeteoGeocodingResponse struct {
+	Results []OpenMeteoLocation `json:"results"`
+}
+
+type OpenMeteoLocation struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Country   string  `json:"country"`
+}
+
+type OpenMeteoForecastResponse struct {
+	Latitude         float64               `json:"latitude"`
+	Longitude        float64               `json:"longitude"`
+	UTCOffsetSeconds int                   `json:"utc_offset_seconds"`
+	Current          *OpenMeteoCurrentData `json:"current"`
+}
+
+type OpenMeteoCurrentData struct {
+	Time                string  `json:"time"`
+	Temperature2m       float64 `json:"temperature_2m"`
+	RelativeHumidity2m  int     `json:"relative_humidity_2m"`
+	ApparentTemperature float64 `json:"apparent_temperature"`
+	WeatherCode         int     `json:"weather_code"`
+	WindSpeed10m        float64 `json:"wind_speed_10m"`
+	WindDirection10m    *int    `json:"wind_direction_10m"`
+	// null в ответе (для точки нет данных) остается nil
+	PressureMSL   *float64 `json:"pressure_msl"` // гПа
+	CloudCover    *int     `json:"cloud_cover"`
+	Precipitation *float64 `json:"precipitation"` // мм
+	Visibility    *float64 `json:"visibility"`    // м
+	UVIndex       *float64 `json:"uv_index"`
+}
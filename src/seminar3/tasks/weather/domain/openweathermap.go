@@ -0,0 +1,38 @@
+package domain
+
+type OpenWeatherMapResponse struct {
+	Name       string                  `json:"name"`
+	Weather    []OpenWeatherMapWeather `json:"weather"`
+	Main       *OpenWeatherMapMain     `json:"main"`
+	Wind       OpenWeatherMapWind      `json:"wind"`
+	Visibility *float64                `json:"visibility"` // м
+	Clouds     *OpenWeatherMapClouds   `json:"clouds"`
+	Rain       OpenWeatherMapPrecip    `json:"rain"`
+	Snow       OpenWeatherMapPrecip    `json:"snow"`
+	Dt         int64                   `json:"dt"`       // время наблюдения, Unix
+	Timezone   int                     `json:"timezone"` // смещение от UTC, секунды
+}
+
+type OpenWeatherMapWeather struct {
+	Description string `json:"description"`
+}
+
+type OpenWeatherMapMain struct {
+	Temp      float64 `json:"temp"`
+	FeelsLike float64 `json:"feels_like"`
+	Humidity  int     `json:"humidity"`
+	Pressure  float64 `json:"pressure"` // гПа
+}
+
+type OpenWeatherMapWind struct {
+	Speed float64 `json:"speed"` // м/с при units=metric
+	Deg   *int    `json:"deg"`   // в штиль может не приходить
+}
+
+type OpenWeatherMapClouds struct {
+	All int `json:"all"` // облачность, %
+}
+
+type OpenWeatherMapPrecip struct {
+	OneHour float64 `json:"1h"` // мм за последний час
+}
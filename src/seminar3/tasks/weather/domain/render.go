@@ -0,0 +1,388 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// Renderer выводит погоду для одного или нескольких городов в w
+type Renderer interface {
+	Render(w io.Writer, items ...*WeatherData) error
+}
+
+// Форматы вывода для NewRenderer
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	FormatCSV      = "csv"
+	FormatLine     = "line"
+	FormatTable    = "table"
+	FormatTemplate = "template"
+)
+
+// NewRenderer создает Renderer по названию формата; tmpl нужен только для FormatTemplate
+func NewRenderer(format string, printer *i18n.Printer, units Units, tmpl string) (Renderer, error) {
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return TextRenderer{Printer: printer, Units: units}, nil
+	case FormatJSON:
+		return JSONRenderer{Units: units}, nil
+	case FormatYAML:
+		return YAMLRenderer{Units: units}, nil
+	case FormatCSV:
+		return CSVRenderer{Units: units}, nil
+	case FormatLine:
+		return LineRenderer{Printer: printer, Units: units}, nil
+	case FormatTable:
+		return TableRenderer{Printer: printer, Units: units}, nil
+	case FormatTemplate:
+		return NewTemplateRenderer(tmpl, units)
+	default:
+		return nil, fmt.Errorf("неизвестный формат вывода %q: ожидается text, json, yaml, csv, line, table или template", format)
+	}
+}
+
+// ForBatch настраивает renderer для пакетного режима: json и yaml выводят
+// массив независимо от числа успешных городов, остальные форматы не меняются
+func ForBatch(renderer Renderer) Renderer {
+	switch r := renderer.(type) {
+	case JSONRenderer:
+		r.Batch = true
+		return r
+	case YAMLRenderer:
+		r.Batch = true
+		return r
+	}
+	return renderer
+}
+
+// Record погода в выбранных единицах для машиночитаемых форматов и шаблонов
+type Record struct {
+	City          string    `json:"city" yaml:"city"`
+	Temperature   float64   `json:"temperature" yaml:"temperature"`
+	FeelsLike     float64   `json:"feels_like" yaml:"feels_like"`
+	Humidity      int       `json:"humidity" yaml:"humidity"`
+	WindSpeed     float64   `json:"wind_speed" yaml:"wind_speed"`
+	WindDirection string    `json:"wind_direction,omitempty" yaml:"wind_direction,omitempty"`
+	WindDegree    *int      `json:"wind_degree,omitempty" yaml:"wind_degree,omitempty"`
+	Pressure      *float64  `json:"pressure,omitempty" yaml:"pressure,omitempty"`
+	Visibility    *float64  `json:"visibility,omitempty" yaml:"visibility,omitempty"`
+	UVIndex       *int      `json:"uv_index,omitempty" yaml:"uv_index,omitempty"`
+	CloudCover    *int      `json:"cloud_cover,omitempty" yaml:"cloud_cover,omitempty"`
+	Precipitation *float64  `json:"precipitation,omitempty" yaml:"precipitation,omitempty"`
+	Description   string    `json:"description" yaml:"description"`
+	ObservedAt    time.Time `json:"observed_at,omitzero" yaml:"observed_at,omitempty"`
+	Sources       []string  `json:"sources,omitempty" yaml:"sources,omitempty"`
+	Units         Units     `json:"units" yaml:"units"`
+}
+
+func NewRecord(w *WeatherData, units Units) Record {
+	record := Record{
+		City:          w.City,
+		Temperature:   round1(w.TemperatureIn(units)),
+		FeelsLike:     round1(w.FeelsLikeIn(units)),
+		Humidity:      w.Humidity,
+		WindSpeed:     round1(w.WindSpeedIn(units)),
+		WindDirection: w.WindDirection,
+		WindDegree:    w.WindDegree,
+		UVIndex:       w.UVIndex,
+		CloudCover:    w.CloudCover,
+		Description:   w.Description,
+		ObservedAt:    w.ObservedAt,
+		Sources:       w.Sources,
+		Units:         units,
+	}
+	if pressure, ok := w.PressureIn(units); ok {
+		record.Pressure = Ptr(round2(pressure))
+	}
+	if visibility, ok := w.VisibilityIn(units); ok {
+		record.Visibility = Ptr(round1(visibility))
+	}
+	if precipitation, ok := w.PrecipitationIn(units); ok {
+		record.Precipitation = Ptr(round2(precipitation))
+	}
+	return record
+}
+
+func newRecords(items []*WeatherData, units Units) []Record {
+	records := make([]Record, len(items))
+	for i, item := range items {
+		records[i] = NewRecord(item, units)
+	}
+	return records
+}
+
+// round1 округляет до десятых, чтобы не выводить 41.000000000001
+func round1(v float64) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'f', 1, 64), 64)
+	return rounded
+}
+
+// round2 округляет до сотых: дюймы ртутного столба и осадков мелкие
+func round2(v float64) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'f', 2, 64), 64)
+	return rounded
+}
+
+// TextRenderer выводит погоду с эмодзи для человека
+type TextRenderer struct {
+	Printer *i18n.Printer
+	Units   Units
+}
+
+func (r TextRenderer) Render(w io.Writer, items ...*WeatherData) error {
+	p := r.Printer
+	for _, item := range items {
+		lines := []string{
+			"",
+			p.T(i18n.WeatherTitle, item.City),
+			p.T(i18n.WeatherTemp, formatTemperature(item.Temperature, r.Units)),
+			p.T(i18n.WeatherFeelsLike, formatTemperature(item.FeelsLike, r.Units)),
+			p.T(i18n.WeatherHumidity, item.Humidity),
+			p.T(i18n.WeatherWind, formatSpeed(item.WindSpeed, r.Units, p)),
+		}
+		lines = append(lines, extendedLines(item, r.Units, p)...)
+		lines = append(lines, p.T(i18n.WeatherDesc, item.Description))
+		if len(item.Sources) > 0 {
+			lines = append(lines, p.T(i18n.WeatherSources, strings.Join(item.Sources, ", ")))
+		}
+		lines = append(lines, p.T(i18n.WeatherTime, time.Now().Format("15:04:05")))
+
+		if _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extendedLines строки с расширенными полями; поля, которых нет у провайдера, пропускаются
+func extendedLines(item *WeatherData, units Units, p *i18n.Printer) []string {
+	var lines []string
+	if item.WindDegree != nil {
+		direction := item.WindDirection
+		if direction == "" {
+			direction = CompassPoint(*item.WindDegree)
+		}
+		lines = append(lines, p.T(i18n.WeatherWindDir, direction, *item.WindDegree))
+	}
+	if pressure, ok := item.PressureIn(units); ok {
+		lines = append(lines, p.T(i18n.WeatherPressure,
+			fmt.Sprintf("%.*f %s", pressurePrecision(units), pressure, p.T(units.PressureUnit()))))
+	}
+	if visibility, ok := item.VisibilityIn(units); ok {
+		lines = append(lines, p.T(i18n.WeatherVisibility,
+			fmt.Sprintf("%.1f %s", visibility, p.T(units.DistanceUnit()))))
+	}
+	if item.UVIndex != nil {
+		lines = append(lines, p.T(i18n.WeatherUV, *item.UVIndex))
+	}
+	if item.CloudCover != nil {
+		lines = append(lines, p.T(i18n.WeatherCloud, *item.CloudCover))
+	}
+	if precipitation, ok := item.PrecipitationIn(units); ok {
+		lines = append(lines, p.T(i18n.WeatherPrecip,
+			fmt.Sprintf("%.*f %s", pressurePrecision(units), precipitation, p.T(units.PrecipitationUnit()))))
+	}
+	if !item.ObservedAt.IsZero() {
+		lines = append(lines, p.T(i18n.WeatherObserved, item.ObservedAt.Format("15:04 MST")))
+	}
+	return lines
+}
+
+// pressurePrecision знаков после запятой: дюймам нужны сотые, гПа и мм - десятые
+func pressurePrecision(units Units) int {
+	if units == Imperial {
+		return 2
+	}
+	return 1
+}
+
+// JSONRenderer выводит объект для одного города и массив для нескольких.
+// С Batch вывод всегда массив, даже если получен только один город
+type JSONRenderer struct {
+	Units Units
+	Batch bool
+}
+
+func (r JSONRenderer) Render(w io.Writer, items ...*WeatherData) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	records := newRecords(items, r.Units)
+	if len(records) == 1 && !r.Batch {
+		return encoder.Encode(records[0])
+	}
+	return encoder.Encode(records)
+}
+
+// YAMLRenderer выводит документ для одного города и список для нескольких.
+// С Batch вывод всегда список, даже если получен только один город
+type YAMLRenderer struct {
+	Units Units
+	Batch bool
+}
+
+func (r YAMLRenderer) Render(w io.Writer, items ...*WeatherData) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+
+	records := newRecords(items, r.Units)
+	if len(records) == 1 && !r.Batch {
+		return encoder.Encode(records[0])
+	}
+	return encoder.Encode(records)
+}
+
+// CSVRenderer выводит таблицу с заголовком; источники разделены ";"
+type CSVRenderer struct {
+	Units Units
+}
+
+var csvHeader = []string{
+	"city", "temperature", "feels_like", "humidity", "wind_speed", "wind_direction", "wind_degree",
+	"pressure", "visibility", "uv_index", "cloud_cover", "precipitation", "description", "observed_at",
+	"sources", "units",
+}
+
+func (r CSVRenderer) Render(w io.Writer, items ...*WeatherData) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, record := range newRecords(items, r.Units) {
+		var observedAt string
+		if !record.ObservedAt.IsZero() {
+			observedAt = record.ObservedAt.Format(time.RFC3339)
+		}
+
+		row := []string{
+			record.City,
+			strconv.FormatFloat(record.Temperature, 'f', 1, 64),
+			strconv.FormatFloat(record.FeelsLike, 'f', 1, 64),
+			strconv.Itoa(record.Humidity),
+			strconv.FormatFloat(record.WindSpeed, 'f', 1, 64),
+			record.WindDirection,
+			formatOptionalInt(record.WindDegree),
+			formatOptionalFloat(record.Pressure, -1),
+			formatOptionalFloat(record.Visibility, 1),
+			formatOptionalInt(record.UVIndex),
+			formatOptionalInt(record.CloudCover),
+			formatOptionalFloat(record.Precipitation, -1),
+			record.Description,
+			observedAt,
+			strings.Join(record.Sources, ";"),
+			string(record.Units),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatOptionalFloat значение для CSV; отсутствующее поле - пустая ячейка
+func formatOptionalFloat(v *float64, prec int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', prec, 64)
+}
+
+// formatOptionalInt значение для CSV; отсутствующее поле - пустая ячейка
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+// LineRenderer выводит по строке на город, например для строки состояния:
+// "Moscow: +5.0°C Cloudy, 💧80%, 💨10.0 км/ч"
+type LineRenderer struct {
+	Printer *i18n.Printer
+	Units   Units
+}
+
+func (r LineRenderer) Render(w io.Writer, items ...*WeatherData) error {
+	for _, item := range items {
+		_, err := fmt.Fprintf(w, "%s: %+.1f%s %s, 💧%d%%, 💨%s\n",
+			item.City, item.TemperatureIn(r.Units), r.Units.TemperatureSymbol(),
+			item.Description, item.Humidity, formatSpeed(item.WindSpeed, r.Units, r.Printer))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TableRenderer выводит таблицу для сравнения нескольких городов
+type TableRenderer struct {
+	Printer *i18n.Printer
+	Units   Units
+}
+
+func (r TableRenderer) Render(w io.Writer, items ...*WeatherData) error {
+	p := r.Printer
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		p.T(i18n.TableCity), p.T(i18n.TableTemp), p.T(i18n.TableFeelsLike),
+		p.T(i18n.TableHumidity), p.T(i18n.TableWind), p.T(i18n.TableDescription))
+	for _, item := range items {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d%%\t%s\t%s\n",
+			item.City, formatTemperature(item.Temperature, r.Units), formatTemperature(item.FeelsLike, r.Units),
+			item.Humidity, formatSpeed(item.WindSpeed, r.Units, p), item.Description)
+	}
+
+	return table.Flush()
+}
+
+// TemplateRenderer выполняет пользовательский text/template для каждого города.
+// В шаблон передается Record, например: "{{.City}} {{.Temperature}}{{.Units}}"
+type TemplateRenderer struct {
+	tmpl  *template.Template
+	units Units
+}
+
+func NewTemplateRenderer(text string, units Units) (*TemplateRenderer, error) {
+	if text == "" {
+		return nil, fmt.Errorf("для формата template нужен шаблон")
+	}
+
+	tmpl, err := template.New("weather").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора шаблона: %w", err)
+	}
+	return &TemplateRenderer{tmpl: tmpl, units: units}, nil
+}
+
+func (r *TemplateRenderer) Render(w io.Writer, items ...*WeatherData) error {
+	for _, item := range items {
+		if err := r.tmpl.Execute(w, NewRecord(item, r.units)); err != nil {
+			return fmt.Errorf("ошибка выполнения шаблона: %w", err)
+		}
+		// шаблон из командной строки обычно без перевода строки в конце
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+var (
+	moscow = &WeatherData{
+		City: "Moscow", Temperature: 5, FeelsLike: 2, Humidity: 80, WindSpeed: 10, Description: "Cloudy",
+		Pressure: Ptr(1018.0), Visibility: Ptr(10.0), UVIndex: Ptr(1), CloudCover: Ptr(100), Precipitation: Ptr(0.5),
+		WindDirection: "SSW", WindDegree: Ptr(203), ObservedAt: time.Date(2025, 10, 5, 9, 5, 0, 0, time.UTC),
+		Sources: []string{"wttrin"},
+	}
+	london = &WeatherData{City: "London", Temperature: 12.26, FeelsLike: 11, Humidity: 70, WindSpeed: 18, Description: "Rain, light"}
+)
+
+func render(t *testing.T, format string, units Units, tmpl string, items ...*WeatherData) string {
+	t.Helper()
+
+	renderer, err := NewRenderer(format, i18n.NewPrinter(i18n.English), units, tmpl)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, renderer.Render(&out, items...))
+	return out.String()
+}
+
+func TestJSONRenderer(t *testing.T) {
+	t.Parallel()
+
+	var single Record
+	require.NoError(t, json.Unmarshal([]byte(render(t, FormatJSON, Imperial, "", moscow)), &single))
+	assert.Equal(t, Record{
+		City: "Moscow", Temperature: 41, FeelsLike: 35.6, Humidity: 80, WindSpeed: 6.2,
+		WindDirection: "SSW", WindDegree: Ptr(203), Pressure: Ptr(30.06), Visibility: Ptr(6.2), UVIndex: Ptr(1),
+		CloudCover: Ptr(100), Precipitation: Ptr(0.02), Description: "Cloudy", ObservedAt: moscow.ObservedAt,
+		Sources: []string{"wttrin"}, Units: Imperial,
+	}, single)
+
+	var many []Record
+	require.NoError(t, json.Unmarshal([]byte(render(t, FormatJSON, Metric, "", moscow, london)), &many))
+	require.Len(t, many, 2)
+	assert.Equal(t, 12.3, many[1].Temperature)
+	assert.NotContains(t, render(t, FormatJSON, Metric, "", london), "wind_degree", "missing wind direction is omitted")
+}
+
+func TestWindDegreeAbsentAndNorth(t *testing.T) {
+	t.Parallel()
+
+	out := render(t, FormatText, Metric, "", london)
+	assert.NotContains(t, out, "Wind direction", "missing direction is not shown as 0°")
+
+	north := &WeatherData{City: "Murmansk", WindDegree: Ptr(0)}
+	assert.Contains(t, render(t, FormatText, Metric, "", north), "Wind direction: N (0°)")
+	assert.Contains(t, render(t, FormatJSON, Metric, "", north), `"wind_degree": 0`)
+}
+
+func TestBatchRendererAlwaysList(t *testing.T) {
+	t.Parallel()
+
+	renderer, err := NewRenderer(FormatJSON, i18n.NewPrinter(i18n.English), Metric, "")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, ForBatch(renderer).Render(&out, moscow))
+	var records []Record
+	require.NoError(t, json.Unmarshal(out.Bytes(), &records), "one successful city in batch is still an array")
+	assert.Len(t, records, 1)
+
+	renderer, err = NewRenderer(FormatYAML, i18n.NewPrinter(i18n.English), Metric, "")
+	require.NoError(t, err)
+	out.Reset()
+	require.NoError(t, ForBatch(renderer).Render(&out, moscow))
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &records))
+	assert.Len(t, records, 1)
+
+	table := TableRenderer{Units: Metric}
+	assert.Equal(t, table, ForBatch(table), "other formats are unchanged")
+}
+
+func TestYAMLRenderer(t *testing.T) {
+	t.Parallel()
+
+	var records []Record
+	require.NoError(t, yaml.Unmarshal([]byte(render(t, FormatYAML, SI, "", moscow, london)), &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, "Moscow", records[0].City)
+	assert.Equal(t, 2.8, records[0].WindSpeed)
+	assert.Equal(t, SI, records[1].Units)
+}
+
+func TestCSVRenderer(t *testing.T) {
+	t.Parallel()
+
+	expected := "city,temperature,feels_like,humidity,wind_speed,wind_direction,wind_degree," +
+		"pressure,visibility,uv_index,cloud_cover,precipitation,description,observed_at,sources,units\n" +
+		"Moscow,5.0,2.0,80,10.0,SSW,203,1018,10.0,1,100,0.5,Cloudy,2025-10-05T09:05:00Z,wttrin,metric\n" +
+		"London,12.3,11.0,70,18.0,,,,,,,,\"Rain, light\",,,metric\n"
+	assert.Equal(t, expected, render(t, FormatCSV, Metric, "", moscow, london))
+}
+
+func TestLineAndTemplateRenderers(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Moscow: +5.0°C Cloudy, 💧80%, 💨10.0 km/h\n", render(t, FormatLine, Metric, "", moscow))
+	assert.Equal(t, "Moscow 41°F wttrin\nLondon 54.1°F \n",
+		render(t, FormatTemplate, Imperial, `{{.City}} {{.Temperature}}{{if eq .Units "imperial"}}°F{{end}} {{join .Sources ","}}`, moscow, london))
+}
+
+func TestTableRenderer(t *testing.T) {
+	t.Parallel()
+
+	expected := "City    Temp    Feels like  Humidity  Wind       Description\n" +
+		"Moscow  5.0°C   2.0°C       80%       10.0 km/h  Cloudy\n" +
+		"London  12.3°C  11.0°C      70%       18.0 km/h  Rain, light\n"
+	assert.Equal(t, expected, render(t, FormatTable, Metric, "", moscow, london))
+}
+
+func TestTextRenderer(t *testing.T) {
+	t.Parallel()
+
+	out := render(t, FormatText, SI, "", moscow)
+	assert.Contains(t, out, "Weather in Moscow")
+	assert.Contains(t, out, "Wind speed: 2.8 m/s")
+	assert.Contains(t, out, "Wind direction: SSW (203°)")
+	assert.Contains(t, out, "Pressure: 1018.0 hPa")
+	assert.Contains(t, out, "UV index: 1")
+	assert.Contains(t, out, "Observed at: 09:05 UTC")
+	assert.Contains(t, out, "Sources: wttrin")
+
+	out = render(t, FormatText, Imperial, "", moscow)
+	assert.Contains(t, out, "Pressure: 30.06 inHg")
+	assert.Contains(t, out, "Visibility: 6.2 mi")
+	assert.Contains(t, out, "Precipitation: 0.02 in")
+
+	out = render(t, FormatText, Metric, "", london)
+	assert.NotContains(t, out, "Pressure", "missing fields are not shown")
+	assert.NotContains(t, out, "Observed")
+
+	clear := &WeatherData{City: "Sochi", UVIndex: Ptr(0), CloudCover: Ptr(0), Precipitation: Ptr(0.0)}
+	out = render(t, FormatText, Metric, "", clear)
+	assert.Contains(t, out, "UV index: 0", "known zero values are shown")
+	assert.Contains(t, out, "Cloud cover: 0%")
+	assert.Contains(t, out, "Precipitation: 0.0 mm")
+}
+
+var forecast = &Forecast{
+	City: "Moscow",
+	Days: []DailyForecast{
+		{
+			Date:    time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC),
+			MinTemp: 1, MaxTemp: 7, AvgTemp: 4.04, Sunrise: "06:58 AM", Sunset: "06:12 PM",
+			Hourly: []HourlyForecast{
+				{Time: time.Date(2025, 10, 5, 9, 0, 0, 0, time.UTC), Temperature: 3, FeelsLike: 1, Humidity: 85, WindSpeed: 10, ChanceOfRain: 40, Description: "Cloudy"},
+				{Time: time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC), Temperature: 6, FeelsLike: 4, Humidity: 70, WindSpeed: 12, ChanceOfRain: 10, Description: "Sunny"},
+			},
+		},
+		{Date: time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC), MinTemp: 0, MaxTemp: 5, AvgTemp: 2},
+	},
+}
+
+func renderForecast(t *testing.T, format string, units Units) string {
+	t.Helper()
+
+	renderer, err := NewRenderer(format, i18n.NewPrinter(i18n.English), units, "")
+	require.NoError(t, err)
+	forecastRenderer, ok := renderer.(ForecastRenderer)
+	require.True(t, ok, format)
+
+	var out bytes.Buffer
+	require.NoError(t, forecastRenderer.RenderForecast(&out, forecast))
+	return out.String()
+}
+
+func TestForecastRenderers(t *testing.T) {
+	t.Parallel()
+
+	var record ForecastRecord
+	require.NoError(t, json.Unmarshal([]byte(renderForecast(t, FormatJSON, Imperial)), &record))
+	assert.Equal(t, "Moscow", record.City)
+	assert.Equal(t, Imperial, record.Units)
+	require.Len(t, record.Days, 2)
+	assert.Equal(t, "2025-10-05", record.Days[0].Date)
+	assert.Equal(t, 39.3, record.Days[0].AvgTemp)
+	assert.Equal(t, ForecastHourRecord{
+		Time: "09:00", Temperature: 37.4, FeelsLike: 33.8, Humidity: 85, WindSpeed: 6.2, ChanceOfRain: 40, Description: "Cloudy",
+	}, record.Days[0].Hourly[0])
+
+	require.NoError(t, yaml.Unmarshal([]byte(renderForecast(t, FormatYAML, Metric)), &record))
+	assert.Equal(t, 7.0, record.Days[0].MaxTemp)
+	assert.Equal(t, "Sunny", record.Days[0].Hourly[1].Description)
+
+	expected := "city,date,min_temp,max_temp,avg_temp,sunrise,sunset,time,temperature,feels_like,humidity,wind_speed,chance_of_rain,description,units\n" +
+		"Moscow,2025-10-05,1.0,7.0,4.0,06:58 AM,06:12 PM,09:00,3.0,1.0,85,10.0,40,Cloudy,metric\n" +
+		"Moscow,2025-10-05,1.0,7.0,4.0,06:58 AM,06:12 PM,12:00,6.0,4.0,70,12.0,10,Sunny,metric\n" +
+		"Moscow,2025-10-06,0.0,5.0,2.0,,,,,,,,,,metric\n"
+	assert.Equal(t, expected, renderForecast(t, FormatCSV, Metric))
+
+	out := renderForecast(t, FormatText, Metric)
+	assert.Contains(t, out, "Moscow")
+	assert.Contains(t, out, "09:00    3.0°C")
+
+	for _, format := range []string{FormatLine, FormatTable} {
+		renderer, err := NewRenderer(format, i18n.NewPrinter(i18n.English), Metric, "")
+		require.NoError(t, err)
+		_, ok := renderer.(ForecastRenderer)
+		assert.False(t, ok, "%s has no forecast layout", format)
+	}
+}
+
+func TestNewRendererErrors(t *testing.T) {
+	t.Parallel()
+
+	printer := i18n.NewPrinter(i18n.Russian)
+
+	_, err := NewRenderer("xml", printer, Metric, "")
+	assert.Error(t, err)
+
+	_, err = NewRenderer(FormatTemplate, printer, Metric, "")
+	assert.Error(t, err)
+
+	_, err = NewRenderer(FormatTemplate, printer, Metric, "{{.City")
+	assert.ErrorContains(t, err, "шаблона")
+}
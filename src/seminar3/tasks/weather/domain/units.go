@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// Units система единиц для вывода. Провайдеры всегда отдают данные
+// в метрической системе (°C, км/ч), перевод делается только при отображении.
+type Units string
+
+const (
+	Metric   Units = "metric"   // °C, км/ч
+	Imperial Units = "imperial" // °F, мили/ч
+	SI       Units = "si"       // °C, м/с
+)
+
+const (
+	kmPerMile  = 1.609344
+	hPaPerInHg = 33.8639
+	mmPerInch  = 25.4
+)
+
+func ParseUnits(value string) (Units, error) {
+	switch units := Units(strings.ToLower(value)); units {
+	case Metric, Imperial, SI:
+		return units, nil
+	default:
+		return "", fmt.Errorf("неизвестная система единиц %q: ожидается metric, imperial или si", value)
+	}
+}
+
+// Temperature переводит градусы Цельсия в единицы системы
+func (u Units) Temperature(celsius float64) float64 {
+	if u == Imperial {
+		return celsius*9/5 + 32
+	}
+	return celsius
+}
+
+// Speed переводит км/ч в единицы системы
+func (u Units) Speed(kmh float64) float64 {
+	switch u {
+	case Imperial:
+		return kmh / kmPerMile
+	case SI:
+		return kmh / 3.6
+	default:
+		return kmh
+	}
+}
+
+// Pressure переводит гПа в единицы системы: дюймы ртутного столба для imperial
+func (u Units) Pressure(hPa float64) float64 {
+	if u == Imperial {
+		return hPa / hPaPerInHg
+	}
+	return hPa
+}
+
+// Distance переводит километры в мили для imperial
+func (u Units) Distance(km float64) float64 {
+	if u == Imperial {
+		return km / kmPerMile
+	}
+	return km
+}
+
+// Precipitation переводит миллиметры в дюймы для imperial
+func (u Units) Precipitation(mm float64) float64 {
+	if u == Imperial {
+		return mm / mmPerInch
+	}
+	return mm
+}
+
+func (u Units) TemperatureSymbol() string {
+	if u == Imperial {
+		return "°F"
+	}
+	return "°C"
+}
+
+// SpeedUnit ключ подписи единицы скорости в каталоге сообщений
+func (u Units) SpeedUnit() i18n.Key {
+	switch u {
+	case Imperial:
+		return i18n.UnitMph
+	case SI:
+		return i18n.UnitMps
+	default:
+		return i18n.UnitKmh
+	}
+}
+
+// PressureUnit ключ подписи единицы давления
+func (u Units) PressureUnit() i18n.Key {
+	if u == Imperial {
+		return i18n.UnitInHg
+	}
+	return i18n.UnitHPa
+}
+
+// DistanceUnit ключ подписи единицы расстояния
+func (u Units) DistanceUnit() i18n.Key {
+	if u == Imperial {
+		return i18n.UnitMi
+	}
+	return i18n.UnitKm
+}
+
+// PrecipitationUnit ключ подписи единицы осадков
+func (u Units) PrecipitationUnit() i18n.Key {
+	if u == Imperial {
+		return i18n.UnitIn
+	}
+	return i18n.UnitMm
+}
+
+// formatTemperature температура с единицами, например "41.0°F"
+func formatTemperature(celsius float64, units Units) string {
+	return fmt.Sprintf("%.1f%s", units.Temperature(celsius), units.TemperatureSymbol())
+}
+
+// formatSpeed скорость с локализованной подписью, например "2.8 м/с"
+func formatSpeed(kmh float64, units Units, printer *i18n.Printer) string {
+	return fmt.Sprintf("%.1f %s", units.Speed(kmh), printer.T(units.SpeedUnit()))
+}
+
+// TemperatureIn температура в единицах units
+func (w *WeatherData) TemperatureIn(units Units) float64 {
+	return units.Temperature(w.Temperature)
+}
+
+// FeelsLikeIn ощущаемая температура в единицах units
+func (w *WeatherData) FeelsLikeIn(units Units) float64 {
+	return units.Temperature(w.FeelsLike)
+}
+
+// WindSpeedIn скорость ветра в единицах units
+func (w *WeatherData) WindSpeedIn(units Units) float64 {
+	return units.Speed(w.WindSpeed)
+}
+
+// PressureIn давление в единицах units; ok = false, если его нет
+func (w *WeatherData) PressureIn(units Units) (float64, bool) {
+	if w.Pressure == nil {
+		return 0, false
+	}
+	return units.Pressure(*w.Pressure), true
+}
+
+// VisibilityIn видимость в единицах units; ok = false, если ее нет
+func (w *WeatherData) VisibilityIn(units Units) (float64, bool) {
+	if w.Visibility == nil {
+		return 0, false
+	}
+	return units.Distance(*w.Visibility), true
+}
+
+// PrecipitationIn осадки в единицах units; ok = false, если их нет
+func (w *WeatherData) PrecipitationIn(units Units) (float64, bool) {
+	if w.Precipitation == nil {
+		return 0, false
+	}
+	return units.Precipitation(*w.Precipitation), true
+}
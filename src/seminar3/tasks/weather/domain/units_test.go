@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUnits(t *testing.T) {
+	t.Parallel()
+
+	units, err := ParseUnits("Imperial")
+	require.NoError(t, err)
+	assert.Equal(t, Imperial, units)
+
+	_, err = ParseUnits("kelvin")
+	assert.Error(t, err)
+}
+
+func TestUnitsConversion(t *testing.T) {
+	t.Parallel()
+
+	data := WeatherData{Temperature: 10, FeelsLike: -40, WindSpeed: 36}
+
+	tests := []struct {
+		units     Units
+		temp      float64
+		feelsLike float64
+		wind      float64
+		symbol    string
+	}{
+		{Metric, 10, -40, 36, "°C"},
+		{Imperial, 50, -40, 22.369, "°F"},
+		{SI, 10, -40, 10, "°C"},
+	}
+
+	for _, tt := range tests {
+		assert.InDelta(t, tt.temp, data.TemperatureIn(tt.units), 0.001, tt.units)
+		assert.InDelta(t, tt.feelsLike, data.FeelsLikeIn(tt.units), 0.001, tt.units)
+		assert.InDelta(t, tt.wind, data.WindSpeedIn(tt.units), 0.001, tt.units)
+		assert.Equal(t, tt.symbol, tt.units.TemperatureSymbol(), tt.units)
+	}
+}
+
+func TestCompassPoint(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int]string{0: "N", 11: "N", 12: "NNE", 90: "E", 203: "SSW", 349: "N", 360: "N", -90: "W"}
+	for degree, expected := range tests {
+		assert.Equal(t, expected, CompassPoint(degree), degree)
+	}
+}
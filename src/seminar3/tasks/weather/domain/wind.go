@@ -0,0 +1,13 @@
+package domain
+
+var compassPoints = [16]string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// CompassPoint переводит направление в градусах в один из 16 румбов, например 203 -> "SSW"
+func CompassPoint(degree int) string {
+	degree = ((degree % 360) + 360) % 360
+	// каждый румб занимает 22.5°, север - от 348.75° до 11.25°
+	return compassPoints[(degree*10+112)/225%16]
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/history"
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// runHistory выводит сводку по сохраненным наблюдениям:
+// weather history [--since 168h | --from 2025-10-01 --to 2025-10-08] [город...]
+func runHistory(args []string) {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	path := flags.String("file", "", "файл истории (по умолчанию history_file из настроек или каталог настроек пользователя)")
+	period := addPeriodFlags(flags)
+	flags.String("units", defaults.Units, "единицы измерения: metric, imperial или si")
+	flags.String("lang", defaults.Lang, "язык вывода: ru или en (по умолчанию из LANG)")
+	output := flags.String("output", domain.FormatText, "формат вывода: text или json")
+	configPath := flags.String("config", "", "файл настроек YAML или JSON")
+	flags.Parse(args)
+
+	cfg := loadConfig(flags, *configPath)
+	if *path == "" {
+		*path = cfg.HistoryFile
+	}
+
+	units, err := domain.ParseUnits(cfg.Units)
+	if err != nil {
+		fatal(err)
+	}
+
+	entries, err := readHistory(*path, period, flags.Args())
+	if err != nil {
+		fatal(err)
+	}
+	summaries := history.Summarize(entries)
+
+	switch *output {
+	case domain.FormatText:
+		if len(summaries) == 0 {
+			fmt.Println(printer.T(i18n.HistoryEmpty))
+			return
+		}
+		err = history.WriteReport(os.Stdout, printer, units, summaries)
+	case domain.FormatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(summaries)
+	default:
+		err = fmt.Errorf("неизвестный формат вывода %q: ожидается text или json", *output)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// periodFlags флаги периода, общие для history и chart
+type periodFlags struct {
+	since    *time.Duration
+	from, to *string
+}
+
+func addPeriodFlags(flags *flag.FlagSet) periodFlags {
+	return periodFlags{
+		since: flags.Duration("since", 7*24*time.Hour, "период до текущего момента, если не задан --from"),
+		from:  flags.String("from", "", "начало периода: 2006-01-02 или 2006-01-02T15:04:05Z07:00"),
+		to:    flags.String("to", "", "конец периода, не включительно (по умолчанию сейчас)"),
+	}
+}
+
+// filter выборка из истории за период по городам cities (все, если пусто)
+func (p periodFlags) filter(cities []string) (history.Filter, error) {
+	filter := history.Filter{Cities: cities}
+
+	var err error
+	if *p.to != "" {
+		if filter.To, err = parseTime(*p.to); err != nil {
+			return history.Filter{}, err
+		}
+	}
+	if *p.from != "" {
+		if filter.From, err = parseTime(*p.from); err != nil {
+			return history.Filter{}, err
+		}
+	} else if *p.since > 0 {
+		end := filter.To
+		if end.IsZero() {
+			end = time.Now()
+		}
+		filter.From = end.Add(-*p.since)
+	}
+	return filter, nil
+}
+
+// readHistory читает записи из журнала path за период
+func readHistory(path string, period periodFlags, cities []string) ([]history.Entry, error) {
+	filter, err := period.filter(cities)
+	if err != nil {
+		return nil, err
+	}
+	store, err := openHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	return store.Read(filter)
+}
+
+// parseTime принимает дату в местном времени или полное время RFC 3339
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("некорректное время %q: ожидается 2006-01-02 или 2006-01-02T15:04:05Z07:00", value)
+	}
+	return t, nil
+}
+
+// openHistory открывает журнал по пути из флага или по пути по умолчанию
+func openHistory(path string) (*history.Store, error) {
+	if path == "" {
+		var err error
+		if path, err = history.DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+	return history.NewStore(path)
+}
+
+// historyOptions настраивает сервис на запись ответов в историю;
+// если журнал открыть не удалось, предупреждает и работает без истории
+func historyOptions(path string) []client.ServiceOption {
+	store, err := openHistory(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, printer.T(i18n.HistoryDisabled, err))
+		return nil
+	}
+	return []client.ServiceOption{client.WithRecorder(&historyRecorder{store: store})}
+}
+
+// historyRecorder дописывает ответы в историю; повторы одного наблюдения, например
+// из кеша, пропускаются. Ошибка записи не мешает выводу погоды, предупреждение
+// печатается один раз.
+type historyRecorder struct {
+	store *history.Store
+	warn  sync.Once
+}
+
+func (r *historyRecorder) Record(data *domain.WeatherData) {
+	if _, err := r.store.AppendNew(history.NewEntry(time.Now(), data)); err != nil {
+		r.warn.Do(func() {
+			fmt.Fprintln(os.Stderr, printer.T(i18n.HistoryDisabled, err))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// Пороги изменения за период, меньше которых показатель считается стабильным
+const (
+	temperatureThreshold = 1.0 // °C
+	humidityThreshold    = 5.0 // %
+	windThreshold        = 3.0 // км/ч
+)
+
+// Trend направление изменения показателя за период
+type Trend int
+
+const (
+	TrendFlat Trend = iota
+	TrendUp
+	TrendDown
+)
+
+func (t Trend) Arrow() string {
+	switch t {
+	case TrendUp:
+		return "↑"
+	case TrendDown:
+		return "↓"
+	default:
+		return "→"
+	}
+}
+
+func (t Trend) MarshalText() ([]byte, error) {
+	switch t {
+	case TrendUp:
+		return []byte("up"), nil
+	case TrendDown:
+		return []byte("down"), nil
+	default:
+		return []byte("flat"), nil
+	}
+}
+
+// Stats минимум, максимум и среднее показателя за период.
+// Change - изменение за весь период по линии тренда (метод наименьших квадратов),
+// поэтому единичный выброс в начале или конце не переворачивает стрелку.
+type Stats struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Avg    float64 `json:"avg"`
+	Change float64 `json:"change"`
+	Trend  Trend   `json:"trend"`
+}
+
+// Summary сводка по одному городу; значения в метрических единицах, как в WeatherData
+type Summary struct {
+	City        string    `json:"city"`
+	Count       int       `json:"count"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Temperature Stats     `json:"temperature"`
+	Humidity    Stats     `json:"humidity"`
+	WindSpeed   Stats     `json:"wind_speed"`
+}
+
+// ByCity группирует записи по городам без учета регистра. Внутри группы записи
+// идут по времени, группы - по алфавиту названия из самой ранней записи.
+func ByCity(entries []Entry) [][]Entry {
+	index := make(map[string]int)
+	var groups [][]Entry
+	for _, entry := range entries {
+		key := cityKey(entry.Data.City)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], entry)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Time.Before(group[j].Time)
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0].Data.City < groups[j][0].Data.City
+	})
+	return groups
+}
+
+// Summarize считает сводки по городам в порядке ByCity
+func Summarize(entries []Entry) []Summary {
+	groups := ByCity(entries)
+	summaries := make([]Summary, len(groups))
+	for i, group := range groups {
+		summaries[i] = summarize(group)
+	}
+	return summaries
+}
+
+// summarize сводка по записям одного города, упорядоченным по времени
+func summarize(entries []Entry) Summary {
+	times := make([]float64, len(entries))
+	temperature := make([]float64, len(entries))
+	humidity := make([]float64, len(entries))
+	wind := make([]float64, len(entries))
+	for i, entry := range entries {
+		times[i] = entry.Time.Sub(entries[0].Time).Hours()
+		temperature[i] = entry.Data.Temperature
+		humidity[i] = float64(entry.Data.Humidity)
+		wind[i] = entry.Data.WindSpeed
+	}
+
+	return Summary{
+		City:        entries[0].Data.City,
+		Count:       len(entries),
+		From:        entries[0].Time,
+		To:          entries[len(entries)-1].Time,
+		Temperature: newStats(times, temperature, temperatureThreshold),
+		Humidity:    newStats(times, humidity, humidityThreshold),
+		WindSpeed:   newStats(times, wind, windThreshold),
+	}
+}
+
+func newStats(times, values []float64, threshold float64) Stats {
+	stats := Stats{Min: math.Inf(1), Max: math.Inf(-1)}
+	var sum float64
+	for _, value := range values {
+		stats.Min = min(stats.Min, value)
+		stats.Max = max(stats.Max, value)
+		sum += value
+	}
+	stats.Avg = sum / float64(len(values))
+
+	stats.Change = slope(times, values) * times[len(times)-1]
+	switch {
+	case stats.Change >= threshold:
+		stats.Trend = TrendUp
+	case stats.Change <= -threshold:
+		stats.Trend = TrendDown
+	}
+	return stats
+}
+
+// slope наклон прямой, лучше всего приближающей точки (x, y); 0, если все x совпадают
+func slope(x, y []float64) float64 {
+	n := float64(len(x))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+		sumXY += x[i] * y[i]
+		sumXX += x[i] * x[i]
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+// WriteReport выводит сводки таблицей в выбранных единицах
+func WriteReport(w io.Writer, printer *i18n.Printer, units domain.Units, summaries []Summary) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	layout := printer.T(i18n.HistoryDateLayout)
+	symbol := units.TemperatureSymbol()
+	speedUnit := printer.T(units.SpeedUnit())
+
+	fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		printer.T(i18n.TableCity), printer.T(i18n.HistoryRecords), printer.T(i18n.HistoryPeriod),
+		printer.T(i18n.HistoryTemp), printer.T(i18n.TableHumidity), printer.T(i18n.TableWind))
+	for _, s := range summaries {
+		fmt.Fprintf(table, "%s\t%d\t%s - %s\t%.1f / %.1f / %.1f%s %s\t%.0f%% %s\t%.1f %s %s\n",
+			s.City, s.Count, s.From.Local().Format(layout), s.To.Local().Format(layout),
+			units.Temperature(s.Temperature.Min), units.Temperature(s.Temperature.Avg),
+			units.Temperature(s.Temperature.Max), symbol, s.Temperature.Trend.Arrow(),
+			s.Humidity.Avg, s.Humidity.Trend.Arrow(),
+			units.Speed(s.WindSpeed.Avg), speedUnit, s.WindSpeed.Trend.Arrow())
+	}
+	return table.Flush()
+}
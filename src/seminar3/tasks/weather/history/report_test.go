@@ -0,0 +1,72 @@
+package history
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	var entries []Entry
+	for i, temperature := range []float64{2, 4, 3, 6, 8} {
+		entries = append(entries, Entry{
+			Time: day.Add(time.Duration(i) * 24 * time.Hour),
+			Data: domain.WeatherData{City: "Moscow", Temperature: temperature, Humidity: 80, WindSpeed: 20 - 4*float64(i)},
+		})
+	}
+	entries = append(entries, entry("london", day, 12), entry("London", day.Add(-time.Hour), 11.5))
+
+	summaries := Summarize(entries)
+	require.Len(t, summaries, 2)
+
+	london := summaries[0]
+	assert.Equal(t, "London", london.City, "name of the earliest entry")
+	assert.Equal(t, 2, london.Count)
+	assert.True(t, day.Add(-time.Hour).Equal(london.From), "entries are ordered by time")
+	assert.Equal(t, TrendFlat, london.Temperature.Trend)
+
+	moscow := summaries[1]
+	assert.Equal(t, 5, moscow.Count)
+	assert.Equal(t, 2.0, moscow.Temperature.Min)
+	assert.Equal(t, 8.0, moscow.Temperature.Max)
+	assert.InDelta(t, 4.6, moscow.Temperature.Avg, 1e-9)
+	assert.InDelta(t, 5.6, moscow.Temperature.Change, 1e-9)
+	assert.Equal(t, TrendUp, moscow.Temperature.Trend)
+	assert.Equal(t, TrendFlat, moscow.Humidity.Trend)
+	assert.Equal(t, TrendDown, moscow.WindSpeed.Trend)
+}
+
+func TestSummarizeSingleEntry(t *testing.T) {
+	t.Parallel()
+
+	summaries := Summarize([]Entry{entry("Moscow", day, 5)})
+	require.Len(t, summaries, 1)
+	assert.Equal(t, Stats{Min: 5, Max: 5, Avg: 5}, summaries[0].Temperature)
+}
+
+func TestWriteReport(t *testing.T) {
+	t.Parallel()
+
+	summaries := []Summary{{
+		City: "Moscow", Count: 5, From: day, To: day.Add(96 * time.Hour),
+		Temperature: Stats{Min: 2, Max: 8, Avg: 4.6, Trend: TrendUp},
+		Humidity:    Stats{Avg: 80},
+		WindSpeed:   Stats{Avg: 12, Trend: TrendDown},
+	}}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteReport(&out, i18n.NewPrinter(i18n.English), domain.Imperial, summaries))
+
+	assert.Contains(t, out.String(), "Temp min / avg / max")
+	assert.Contains(t, out.String(), "35.6 / 40.3 / 46.4°F ↑")
+	assert.Contains(t, out.String(), "80% →")
+	assert.Contains(t, out.String(), "7.5 mph ↓")
+}
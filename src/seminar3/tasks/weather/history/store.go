@@ -0,0 +1,209 @@
+// Package history сохраняет полученную погоду в журнал на диске
+// и строит по нему сводки за период.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// maxLineSize предел длины одной записи при чтении журнала
+const maxLineSize = 1 << 20
+
+// Entry одно наблюдение: когда получено и что
+type Entry struct {
+	Time time.Time          `json:"time"`
+	Data domain.WeatherData `json:"data"`
+}
+
+func NewEntry(at time.Time, data *domain.WeatherData) Entry {
+	return Entry{Time: at, Data: *data}
+}
+
+// Filter отбирает записи журнала; нулевые поля не ограничивают выборку
+type Filter struct {
+	From   time.Time // включительно
+	To     time.Time // не включительно
+	Cities []string  // без учета регистра
+}
+
+func (f Filter) match(entry Entry) bool {
+	if !f.From.IsZero() && entry.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !entry.Time.Before(f.To) {
+		return false
+	}
+	if len(f.Cities) == 0 {
+		return true
+	}
+
+	city := cityKey(entry.Data.City)
+	for _, wanted := range f.Cities {
+		if cityKey(wanted) == city {
+			return true
+		}
+	}
+	return false
+}
+
+// Store журнал в формате JSON Lines: по записи на строку, файл только дописывается
+type Store struct {
+	mu   sync.Mutex
+	path string
+	// observed последнее время наблюдения по городам для AppendNew;
+	// читается из журнала при первом вызове
+	observed map[string]time.Time
+}
+
+// DefaultPath путь к журналу в каталоге настроек пользователя, например ~/.config/weather/history.jsonl
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("не удалось определить каталог для истории: %w", err)
+	}
+	return filepath.Join(dir, "weather", "history.jsonl"), nil
+}
+
+func NewStore(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("ошибка создания каталога истории: %w", err)
+	}
+	return &Store{path: path}, nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append дописывает записи одним вызовом Write, чтобы строки разных процессов не перемешивались
+func (s *Store) Append(entries ...Entry) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("ошибка сериализации записи истории: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.write(buf.Bytes()); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		s.remember(entry)
+	}
+	return nil
+}
+
+// AppendNew дописывает запись, если это новое наблюдение: ответ с тем же
+// городом и временем наблюдения, что у последней записи (например, из кеша),
+// пропускается. Записи без времени наблюдения дописываются всегда.
+func (s *Store) AppendNew(entry Entry) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	observedAt := entry.Data.ObservedAt
+	if !observedAt.IsZero() {
+		if s.observed == nil {
+			entries, err := s.read(Filter{})
+			if err != nil {
+				return false, err
+			}
+			s.observed = make(map[string]time.Time)
+			for _, e := range entries {
+				s.remember(e)
+			}
+		}
+		if last, ok := s.observed[cityKey(entry.Data.City)]; ok && last.Equal(observedAt) {
+			return false, nil
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return false, fmt.Errorf("ошибка сериализации записи истории: %w", err)
+	}
+	if err := s.write(append(data, '\n')); err != nil {
+		return false, err
+	}
+	s.remember(entry)
+	return true, nil
+}
+
+// remember запоминает время наблюдения записи, если индекс уже загружен
+func (s *Store) remember(entry Entry) {
+	if s.observed != nil && !entry.Data.ObservedAt.IsZero() {
+		s.observed[cityKey(entry.Data.City)] = entry.Data.ObservedAt
+	}
+}
+
+// write дописывает data в журнал; вызывается под s.mu
+func (s *Store) write(data []byte) error {
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия истории: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("ошибка записи истории: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("ошибка записи истории: %w", err)
+	}
+	return nil
+}
+
+// Read возвращает подходящие под filter записи в порядке записи.
+// Отсутствующий журнал - это пустая история; испорченные строки, например
+// оборванные при аварийном завершении, пропускаются.
+func (s *Store) Read(filter Filter) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(filter)
+}
+
+// read читает журнал; вызывается под s.mu
+func (s *Store) read(filter Filter) ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия истории: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if filter.match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения истории: %w", err)
+	}
+	return entries, nil
+}
+
+func cityKey(city string) string {
+	return strings.ToLower(strings.Join(strings.Fields(city), " "))
+}
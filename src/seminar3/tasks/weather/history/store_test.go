@@ -0,0 +1,139 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+var day = time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func entry(city string, at time.Time, temperature float64) Entry {
+	return Entry{Time: at, Data: domain.WeatherData{City: city, Temperature: temperature}}
+}
+
+func TestStoreAppendAndRead(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(filepath.Join(t.TempDir(), "nested", "history.jsonl"))
+	require.NoError(t, err)
+
+	entries, err := store.Read(Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries, "missing file is an empty history")
+
+	require.NoError(t, store.Append(entry("Moscow", day, 5), entry("London", day, 12)))
+	require.NoError(t, store.Append(entry("Moscow", day.Add(24*time.Hour), 7)))
+
+	entries, err = store.Read(Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "London", entries[1].Data.City)
+	assert.True(t, day.Equal(entries[1].Time))
+}
+
+func TestStoreAppendNew(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store, err := NewStore(path)
+	require.NoError(t, err)
+
+	observed := entry("Moscow", day, 5)
+	observed.Data.ObservedAt = day.Add(-10 * time.Minute)
+	require.NoError(t, store.Append(observed))
+
+	// тот же ответ из кеша при следующем запуске
+	reopened, err := NewStore(path)
+	require.NoError(t, err)
+	repeat := observed
+	repeat.Time = day.Add(5 * time.Minute)
+	added, err := reopened.AppendNew(repeat)
+	require.NoError(t, err)
+	assert.False(t, added, "same observation is not recorded twice")
+
+	fresh := repeat
+	fresh.Data.ObservedAt = day.Add(5 * time.Minute)
+	added, err = reopened.AppendNew(fresh)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = reopened.AppendNew(fresh)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	added, err = reopened.AppendNew(entry("London", day, 12))
+	require.NoError(t, err)
+	assert.True(t, added, "entries without observation time are always recorded")
+
+	entries, err := reopened.Read(Filter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestStoreReadFilter(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	require.NoError(t, err)
+	require.NoError(t, store.Append(
+		entry("Moscow", day, 1),
+		entry("London", day.Add(time.Hour), 2),
+		entry("Moscow", day.Add(2*time.Hour), 3),
+		entry("Moscow", day.Add(3*time.Hour), 4),
+	))
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []float64
+	}{
+		{name: "all", filter: Filter{}, expected: []float64{1, 2, 3, 4}},
+		{name: "city ignores case", filter: Filter{Cities: []string{" moscow"}}, expected: []float64{1, 3, 4}},
+		{name: "from inclusive", filter: Filter{From: day.Add(2 * time.Hour)}, expected: []float64{3, 4}},
+		{name: "to exclusive", filter: Filter{To: day.Add(2 * time.Hour)}, expected: []float64{1, 2}},
+		{name: "no match", filter: Filter{Cities: []string{"Paris"}}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := store.Read(tt.filter)
+			require.NoError(t, err)
+
+			var temperatures []float64
+			for _, e := range entries {
+				temperatures = append(temperatures, e.Data.Temperature)
+			}
+			assert.Equal(t, tt.expected, temperatures)
+		})
+	}
+}
+
+func TestStoreSkipsCorruptedLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store, err := NewStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Append(entry("Moscow", day, 5)))
+
+	// оборванная при аварийном завершении строка
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"time":"2025-10-01T13:00:00Z","data":{"ci` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	require.NoError(t, store.Append(entry("Moscow", day.Add(2*time.Hour), 6)))
+
+	entries, err := store.Read(Filter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
@@ -0,0 +1,101 @@
+package i18n
+
+var english = map[Key]string{
+	WeatherTitle:      "🌤️  Weather in %s",
+	WeatherTemp:       "🌡️  Temperature: %s",
+	WeatherFeelsLike:  "🤔 Feels like: %s",
+	WeatherHumidity:   "💧 Humidity: %d%%",
+	WeatherWind:       "💨 Wind speed: %s",
+	WeatherDesc:       "📝 Description: %s",
+	WeatherSources:    "🔗 Sources: %s",
+	WeatherWindDir:    "🧭 Wind direction: %s (%d°)",
+	WeatherPressure:   "📈 Pressure: %s",
+	WeatherVisibility: "👁️  Visibility: %s",
+	WeatherUV:         "🔆 UV index: %d",
+	WeatherCloud:      "☁️  Cloud cover: %d%%",
+	WeatherPrecip:     "🌧️  Precipitation: %s",
+	WeatherObserved:   "📡 Observed at: %s",
+	WeatherTime:       "🕒 Requested at: %s",
+
+	ForecastTitle:      "📅 Weather forecast for %s",
+	ForecastTemp:       "🌡️  Min/max: %s / %s (average %s)",
+	ForecastSun:        "🌅 Sunrise: %s, 🌇 sunset: %s",
+	ForecastDateLayout: "Mon, 02 Jan 2006",
+
+	TableCity:        "City",
+	TableTemp:        "Temp",
+	TableFeelsLike:   "Feels like",
+	TableHumidity:    "Humidity",
+	TableWind:        "Wind",
+	TableDescription: "Description",
+
+	HistoryRecords:    "Records",
+	HistoryPeriod:     "Period",
+	HistoryTemp:       "Temp min / avg / max",
+	HistoryDateLayout: "Jan 02 15:04",
+	HistoryEmpty:      "No records for the selected period",
+
+	ChartTemperature:   "Temperature, %s",
+	ChartFeelsLike:     "%s, feels like",
+	ChartHumidity:      "Humidity, %",
+	ChartTimeLayout:    "Jan 02\n15:04",
+	ChartForecastTitle: "Weather forecast",
+	ChartHistoryTitle:  "Weather history",
+
+	AlertTriggered: "🚨 [%s] %s: %s (%s = %.1f)",
+	AlertResolved:  "✅ [%s] %s: cleared %s (%s = %.1f)",
+	WatchStarted:   "👀 Watching %d cities with %d rules, polling every %s",
+
+	ConfigFile:   "Config file: %s",
+	ConfigKey:    "KEY",
+	ConfigValue:  "VALUE",
+	ConfigSource: "SOURCE",
+
+	UnitKmh:  "km/h",
+	UnitMph:  "mph",
+	UnitMps:  "m/s",
+	UnitHPa:  "hPa",
+	UnitInHg: "inHg",
+	UnitKm:   "km",
+	UnitMi:   "mi",
+	UnitMm:   "mm",
+	UnitIn:   "in",
+
+	Usage: `Usage: weather [flags] <city>
+Example: weather Moscow
+Example: weather "New York"
+Example: weather London
+Example: weather 55.75,37.62 (coordinates), weather airport:SVO (airport), weather auto (by IP)
+Example: weather --forecast 3 Moscow
+Example: weather Moscow London Paris
+Example: weather --file offices.txt (or --file - to read stdin)
+Example: weather --provider wttrin,open-meteo Moscow
+Example: weather --units imperial --lang en London
+History: weather history --since 168h Moscow (weather history -h for flags)
+Chart: weather chart --forecast 3 --out moscow.png Moscow (weather chart -h for flags)
+Alerts: weather watch --rule "temperature < -15" --rule "wind_speed > 50" Moscow (weather watch -h for flags)
+Settings: weather config show (config.yaml file, WEATHER_* variables, flags)
+Server: weather serve --addr :8080 (weather serve -h for server flags)
+
+Exit codes: 1 - error, 2 - some cities failed, 3 - city not found,
+4 - rate limited, 5 - service unavailable, 6 - malformed service response`,
+	UsageFlags:        "\nFlags:",
+	RequestWeather:    "Fetching weather for: %s",
+	RequestForecast:   "Fetching %d-day forecast for: %s",
+	RequestBatch:      "Fetching weather for %d cities",
+	BatchCityError:    "❌ %s: %v",
+	BatchSummary:      "Got weather for %d of %d cities",
+	ErrorMessage:      "❌ Error: %v",
+	CacheDisabled:     "⚠️  Cache disabled: %v",
+	HistoryDisabled:   "⚠️  History is not saved: %v",
+	ChartSaved:        "📊 Chart saved to %s",
+	HintsTitle:        "\nHints:",
+	HintCheckCity:     "- Check the city name",
+	HintEnglishName:   "- Try the English name for international cities",
+	HintCheckInternet: "- Make sure you are connected to the internet",
+	HintRateLimited:   "- The weather service is rate limiting requests, try again later",
+	HintUseCache:      "- Enable the cache to avoid repeating the same requests (--cache-ttl 10m)",
+	HintOtherProvider: "- Try another data source or several at once (--provider wttrin,open-meteo)",
+	HintBadResponse:   "- The service returned a response in an unexpected format",
+	HintLocation:      "- A location is a city name, coordinates like 55.75,37.62, an airport code like airport:SVO or auto",
+}
@@ -0,0 +1,90 @@
+// Package i18n хранит каталоги сообщений CLI погоды и выбирает язык
+// по флагу или переменным окружения LC_ALL, LC_MESSAGES, LANG.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Lang код языка в формате ISO 639-1
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+
+	DefaultLang = Russian
+)
+
+// Key идентификатор сообщения в каталоге
+type Key string
+
+var catalogs = map[Lang]map[Key]string{
+	Russian: russian,
+	English: english,
+}
+
+// ParseLang разбирает код языка или локаль вида en_US.UTF-8
+func ParseLang(value string) (Lang, error) {
+	code := strings.ToLower(value)
+	if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+		code = code[:i]
+	}
+
+	lang := Lang(code)
+	if _, ok := catalogs[lang]; !ok {
+		return "", fmt.Errorf("неподдерживаемый язык %q: ожидается ru или en", value)
+	}
+	return lang, nil
+}
+
+// Detect выбирает язык: значение флага, затем локаль из окружения, иначе DefaultLang
+func Detect(flagValue string) Lang {
+	candidates := []string{flagValue, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, candidate := range candidates {
+		if candidate == "" || candidate == "C" || candidate == "POSIX" {
+			continue
+		}
+		if lang, err := ParseLang(candidate); err == nil {
+			return lang
+		}
+	}
+	return DefaultLang
+}
+
+// Printer форматирует сообщения на выбранном языке
+type Printer struct {
+	lang    Lang
+	catalog map[Key]string
+}
+
+func NewPrinter(lang Lang) *Printer {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		lang, catalog = DefaultLang, catalogs[DefaultLang]
+	}
+	return &Printer{lang: lang, catalog: catalog}
+}
+
+func (p *Printer) Lang() Lang {
+	return p.lang
+}
+
+// T возвращает сообщение по ключу, подставляя args как в fmt.Sprintf.
+// Если перевода нет, берется русский вариант, а в крайнем случае сам ключ.
+func (p *Printer) T(key Key, args ...any) string {
+	format, ok := p.catalog[key]
+	if !ok {
+		format, ok = catalogs[DefaultLang][key]
+	}
+	if !ok {
+		format = string(key)
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,72 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	t.Parallel()
+
+	for lang, catalog := range catalogs {
+		for key := range catalogs[DefaultLang] {
+			assert.Contains(t, catalog, key, "language %s", lang)
+		}
+		assert.Len(t, catalog, len(catalogs[DefaultLang]), "language %s", lang)
+	}
+}
+
+func TestParseLang(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected Lang
+		wantErr  bool
+	}{
+		{"ru", Russian, false},
+		{"EN", English, false},
+		{"en_US.UTF-8", English, false},
+		{"ru_RU.UTF-8", Russian, false},
+		{"de_DE", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		lang, err := ParseLang(tt.value)
+		if tt.wantErr {
+			assert.Error(t, err, tt.value)
+			continue
+		}
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, lang, tt.value)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "en_GB.UTF-8")
+
+	assert.Equal(t, English, Detect(""))
+	assert.Equal(t, Russian, Detect("ru"), "flag wins over environment")
+
+	t.Setenv("LC_ALL", "C")
+	assert.Equal(t, English, Detect(""), "C locale is skipped")
+
+	t.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, DefaultLang, Detect(""))
+}
+
+func TestPrinter(t *testing.T) {
+	t.Parallel()
+
+	en := NewPrinter(English)
+	assert.Equal(t, "🌤️  Weather in Moscow", en.T(WeatherTitle, "Moscow"))
+	assert.Equal(t, "km/h", en.T(UnitKmh))
+	assert.Equal(t, "missing.key", en.T("missing.key"))
+
+	assert.Equal(t, DefaultLang, NewPrinter("fr").Lang())
+}
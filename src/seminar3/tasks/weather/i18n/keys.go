@@ -0,0 +1,109 @@
+package i18n
+
+// Текущая погода
+const (
+	WeatherTitle      Key = "weather.title"
+	WeatherTemp       Key = "weather.temperature"
+	WeatherFeelsLike  Key = "weather.feels_like"
+	WeatherHumidity   Key = "weather.humidity"
+	WeatherWind       Key = "weather.wind"
+	WeatherDesc       Key = "weather.description"
+	WeatherSources    Key = "weather.sources"
+	WeatherWindDir    Key = "weather.wind_direction"
+	WeatherPressure   Key = "weather.pressure"
+	WeatherVisibility Key = "weather.visibility"
+	WeatherUV         Key = "weather.uv_index"
+	WeatherCloud      Key = "weather.cloud_cover"
+	WeatherPrecip     Key = "weather.precipitation"
+	WeatherObserved   Key = "weather.observed_at"
+	WeatherTime       Key = "weather.time"
+)
+
+// Прогноз
+const (
+	ForecastTitle      Key = "forecast.title"
+	ForecastTemp       Key = "forecast.temperature"
+	ForecastSun        Key = "forecast.sun"
+	ForecastDateLayout Key = "forecast.date_layout"
+)
+
+// Таблица сравнения городов
+const (
+	TableCity        Key = "table.city"
+	TableTemp        Key = "table.temperature"
+	TableFeelsLike   Key = "table.feels_like"
+	TableHumidity    Key = "table.humidity"
+	TableWind        Key = "table.wind"
+	TableDescription Key = "table.description"
+)
+
+// История наблюдений
+const (
+	HistoryRecords    Key = "history.records"
+	HistoryPeriod     Key = "history.period"
+	HistoryTemp       Key = "history.temperature"
+	HistoryDateLayout Key = "history.date_layout"
+	HistoryEmpty      Key = "history.empty"
+)
+
+// Графики
+const (
+	ChartTemperature   Key = "chart.temperature"
+	ChartFeelsLike     Key = "chart.feels_like"
+	ChartHumidity      Key = "chart.humidity"
+	ChartTimeLayout    Key = "chart.time_layout"
+	ChartForecastTitle Key = "chart.forecast_title"
+	ChartHistoryTitle  Key = "chart.history_title"
+)
+
+// Оповещения
+const (
+	AlertTriggered Key = "alert.triggered"
+	AlertResolved  Key = "alert.resolved"
+	WatchStarted   Key = "alert.watch_started"
+)
+
+// Настройки
+const (
+	ConfigFile   Key = "config.file"
+	ConfigKey    Key = "config.key"
+	ConfigValue  Key = "config.value"
+	ConfigSource Key = "config.source"
+)
+
+// Единицы измерения
+const (
+	UnitKmh  Key = "unit.kmh"
+	UnitMph  Key = "unit.mph"
+	UnitMps  Key = "unit.mps"
+	UnitHPa  Key = "unit.hpa"
+	UnitInHg Key = "unit.inhg"
+	UnitKm   Key = "unit.km"
+	UnitMi   Key = "unit.mi"
+	UnitMm   Key = "unit.mm"
+	UnitIn   Key = "unit.in"
+)
+
+// Сообщения CLI
+const (
+	Usage             Key = "cli.usage"
+	UsageFlags        Key = "cli.usage_flags"
+	RequestWeather    Key = "cli.request_weather"
+	RequestForecast   Key = "cli.request_forecast"
+	RequestBatch      Key = "cli.request_batch"
+	BatchCityError    Key = "cli.batch_city_error"
+	BatchSummary      Key = "cli.batch_summary"
+	ErrorMessage      Key = "cli.error"
+	CacheDisabled     Key = "cli.cache_disabled"
+	HistoryDisabled   Key = "cli.history_disabled"
+	ChartSaved        Key = "cli.chart_saved"
+	HintsTitle        Key = "cli.hints"
+	HintCheckCity     Key = "cli.hint_city"
+	HintEnglishName   Key = "cli.hint_english"
+	HintCheckInternet Key = "cli.hint_internet"
+	HintRateLimited   Key = "cli.hint_rate_limited"
+	HintUseCache      Key = "cli.hint_use_cache"
+	HintOtherProvider Key = "cli.hint_other_provider"
+	HintBadResponse   Key = "cli.hint_bad_response"
+	HintLocation      Key = "cli.hint_location"
+)
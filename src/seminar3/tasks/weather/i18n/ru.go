@@ -0,0 +1,101 @@
+package i18n
+
+var russian = map[Key]string{
+	WeatherTitle:      "🌤️  Погода в %s",
+	WeatherTemp:       "🌡️  Температура: %s",
+	WeatherFeelsLike:  "🤔 Ощущается как: %s",
+	WeatherHumidity:   "💧 Влажность: %d%%",
+	WeatherWind:       "💨 Скорость ветра: %s",
+	WeatherDesc:       "📝 Описание: %s",
+	WeatherSources:    "🔗 Источники: %s",
+	WeatherWindDir:    "🧭 Направление ветра: %s (%d°)",
+	WeatherPressure:   "📈 Давление: %s",
+	WeatherVisibility: "👁️  Видимость: %s",
+	WeatherUV:         "🔆 УФ-индекс: %d",
+	WeatherCloud:      "☁️  Облачность: %d%%",
+	WeatherPrecip:     "🌧️  Осадки: %s",
+	WeatherObserved:   "📡 Время наблюдения: %s",
+	WeatherTime:       "🕒 Время запроса: %s",
+
+	ForecastTitle:      "📅 Прогноз погоды в %s",
+	ForecastTemp:       "🌡️  Мин/макс: %s / %s (средняя %s)",
+	ForecastSun:        "🌅 Восход: %s, 🌇 закат: %s",
+	ForecastDateLayout: "02.01.2006",
+
+	TableCity:        "Город",
+	TableTemp:        "Темп.",
+	TableFeelsLike:   "Ощущается",
+	TableHumidity:    "Влажность",
+	TableWind:        "Ветер",
+	TableDescription: "Описание",
+
+	HistoryRecords:    "Записей",
+	HistoryPeriod:     "Период",
+	HistoryTemp:       "Темп. мин / сред / макс",
+	HistoryDateLayout: "02.01 15:04",
+	HistoryEmpty:      "Нет записей за выбранный период",
+
+	ChartTemperature:   "Температура, %s",
+	ChartFeelsLike:     "%s, ощущается",
+	ChartHumidity:      "Влажность, %",
+	ChartTimeLayout:    "02.01\n15:04",
+	ChartForecastTitle: "Прогноз погоды",
+	ChartHistoryTitle:  "История наблюдений",
+
+	AlertTriggered: "🚨 [%s] %s: %s (%s = %.1f)",
+	AlertResolved:  "✅ [%s] %s: снято %s (%s = %.1f)",
+	WatchStarted:   "👀 Слежу за городами: %d, правил: %d, опрос каждые %s",
+
+	ConfigFile:   "Файл настроек: %s",
+	ConfigKey:    "ПАРАМЕТР",
+	ConfigValue:  "ЗНАЧЕНИЕ",
+	ConfigSource: "ИСТОЧНИК",
+
+	UnitKmh:  "км/ч",
+	UnitMph:  "миль/ч",
+	UnitMps:  "м/с",
+	UnitHPa:  "гПа",
+	UnitInHg: "дюйм рт. ст.",
+	UnitKm:   "км",
+	UnitMi:   "миль",
+	UnitMm:   "мм",
+	UnitIn:   "дюйм",
+
+	Usage: `Использование: weather [флаги] <город>
+Пример: weather Moscow
+Пример: weather "New York"
+Пример: weather Лондон
+Пример: weather 55.75,37.62 (координаты), weather airport:SVO (аэропорт), weather auto (по IP)
+Пример: weather --forecast 3 Moscow
+Пример: weather Moscow London Paris
+Пример: weather --file offices.txt (или --file - для чтения из stdin)
+Пример: weather --provider wttrin,open-meteo Moscow
+Пример: weather --units imperial --lang en London
+История: weather history --since 168h Moscow (weather history -h - флаги)
+График: weather chart --forecast 3 --out moscow.png Moscow (weather chart -h - флаги)
+Оповещения: weather watch --rule "temperature < -15" --rule "wind_speed > 50" Moscow (weather watch -h - флаги)
+Настройки: weather config show (файл config.yaml, переменные WEATHER_*, флаги)
+Сервер: weather serve --addr :8080 (weather serve -h - флаги сервера)
+
+Коды выхода: 1 - ошибка, 2 - получены не все города, 3 - город не найден,
+4 - превышен лимит запросов, 5 - сервис недоступен, 6 - некорректный ответ сервиса`,
+	UsageFlags:        "\nФлаги:",
+	RequestWeather:    "Запрашиваю погоду для города: %s",
+	RequestForecast:   "Запрашиваю прогноз на %d дн. для города: %s",
+	RequestBatch:      "Запрашиваю погоду для городов: %d",
+	BatchCityError:    "❌ %s: %v",
+	BatchSummary:      "Получены данные для %d из %d городов",
+	ErrorMessage:      "❌ Ошибка: %v",
+	CacheDisabled:     "⚠️  Кеш отключен: %v",
+	HistoryDisabled:   "⚠️  История не сохраняется: %v",
+	ChartSaved:        "📊 График сохранен в %s",
+	HintsTitle:        "\nПодсказки:",
+	HintCheckCity:     "- Проверьте название города",
+	HintEnglishName:   "- Попробуйте английское название для международных городов",
+	HintCheckInternet: "- Убедитесь, что есть интернет-соединение",
+	HintRateLimited:   "- Сервис погоды ограничил число запросов, повторите позже",
+	HintUseCache:      "- Включите кеш, чтобы не повторять одни и те же запросы (--cache-ttl 10m)",
+	HintOtherProvider: "- Попробуйте другой источник данных или несколько сразу (--provider wttrin,open-meteo)",
+	HintBadResponse:   "- Сервис вернул ответ в неожиданном формате",
+	HintLocation:      "- Место задается названием города, координатами 55.75,37.62, кодом аэропорта airport:SVO или auto",
+}
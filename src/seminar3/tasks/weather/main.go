@@ -1,37 +1,352 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/i18n"
 )
 
+// Коды выхода, на которые могут опираться скрипты
+const (
+	exitError        = 1   // неверные аргументы и прочие ошибки
+	exitPartial      = 2   // получены данные не для всех городов
+	exitCityNotFound = 3   // город не найден
+	exitRateLimited  = 4   // превышен лимит запросов
+	exitUnavailable  = 5   // сервис погоды недоступен или не ответил вовремя
+	exitBadResponse  = 6   // сервис вернул некорректный ответ
+	exitInterrupted  = 130 // прервано Ctrl-C
+)
+
+// printer язык сообщений; до разбора флагов берется из окружения
+var printer = i18n.NewPrinter(i18n.Detect(""))
+
+func usage() {
+	fmt.Println(printer.T(i18n.Usage))
+	fmt.Println(printer.T(i18n.UsageFlags))
+	flag.PrintDefaults()
+}
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Использование: weather <город>")
-		fmt.Println("Пример: weather Moscow")
-		fmt.Println("Пример: weather \"New York\"")
-		fmt.Println("Пример: weather Лондон")
-		os.Exit(1)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		case "chart":
+			runChart(os.Args[2:])
+			return
+		case "watch":
+			runWatch(os.Args[2:])
+			return
+		case "config":
+			runConfig(os.Args[2:])
+			return
+		}
+	}
+
+	forecastDays := flag.Int("forecast", 0, "прогноз на N дней (1-3) вместо текущей погоды")
+	tmpl := flag.String("template", "", "шаблон text/template для --output template, например '{{.City}}: {{.Temperature}}'")
+	citiesFile := flag.String("file", "", "файл со списком городов, по одному на строку (- для stdin)")
+	verbose := flag.Bool("verbose", false, "выводить ход запросов и повторные попытки в stderr")
+	addSettingFlags(flag.CommandLine)
+	configPath := addConfigFlags(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+
+	cfg := loadConfig(flag.CommandLine, *configPath)
+
+	units, err := domain.ParseUnits(cfg.Units)
+	if err != nil {
+		fatal(err)
+	}
+
+	cities := flag.Args()
+	if *citiesFile != "" {
+		fromFile, err := readCities(*citiesFile)
+		if err != nil {
+			fatal(err)
+		}
+		cities = append(cities, fromFile...)
+	}
+	if len(cities) == 0 {
+		usage()
+		os.Exit(exitError)
+	}
+	cities, err = normalizeLocations(cities)
+	if err != nil {
+		fail(err)
+	}
+
+	format := cfg.Output
+	if *tmpl != "" && format == domain.FormatText {
+		format = domain.FormatTemplate
+	}
+	// несколько городов по умолчанию выводим таблицей для сравнения
+	if format == domain.FormatText && len(cities) > 1 {
+		format = domain.FormatTable
+	}
+	renderer, err := domain.NewRenderer(format, printer, units, *tmpl)
+	if err != nil {
+		fatal(err)
+	}
+	// машиночитаемый вывод не разбавляем сообщениями о ходе запроса
+	chatty := format == domain.FormatText || format == domain.FormatTable
+
+	// Ctrl-C прерывает запрос и ожидание между повторными попытками
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	provider, err := newCompositeProvider(strings.Split(cfg.Provider, ","), cfg.Aggregate, cfg.CacheTTL, providerOptions(cfg, *verbose)...)
+	if err != nil {
+		fatal(err)
+	}
+	serviceOptions := []client.ServiceOption{
+		client.WithConcurrency(cfg.Concurrency),
+		client.WithBatchTimeout(cfg.Timeout),
+	}
+	if cfg.History {
+		serviceOptions = append(serviceOptions, historyOptions(cfg.HistoryFile)...)
+	}
+	service := client.NewWeatherService(provider, serviceOptions...)
+
+	if len(cities) > 1 {
+		if *forecastDays > 0 {
+			fatal(errors.New("прогноз запрашивается только для одного города"))
+		}
+		runBatch(ctx, service, cities, domain.ForBatch(renderer), chatty)
+		return
+	}
+
+	city := cities[0]
+
+	if *forecastDays > 0 {
+		forecastRenderer, ok := renderer.(domain.ForecastRenderer)
+		if !ok {
+			fatal(fmt.Errorf("прогноз выводится только в форматах text, json, yaml и csv, а не %s", format))
+		}
+		if chatty {
+			fmt.Println(printer.T(i18n.RequestForecast, *forecastDays, city))
+		}
+
+		forecast, err := service.GetForecast(ctx, city, *forecastDays)
+		if err != nil {
+			fail(err)
+		}
+
+		if err := forecastRenderer.RenderForecast(os.Stdout, forecast); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	if chatty {
+		fmt.Println(printer.T(i18n.RequestWeather, city))
+	}
+
+	data, err := service.GetWeather(ctx, city)
+	if err != nil {
+		fail(err)
+	}
+
+	if err := renderer.Render(os.Stdout, data); err != nil {
+		fail(err)
 	}
+}
+
+// runBatch запрашивает несколько городов и выводит успешные ответы вместе,
+// ошибки по городам печатаются отдельно. Код выхода exitError, если не получен
+// ни один город, и exitPartial, если не получены только некоторые.
+func runBatch(ctx context.Context, service *client.WeatherService, cities []string, renderer domain.Renderer, chatty bool) {
+	if chatty {
+		fmt.Println(printer.T(i18n.RequestBatch, len(cities)))
+	}
+
+	results := service.GetWeatherBatch(ctx, cities)
+
+	var items []*domain.WeatherData
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintln(os.Stderr, printer.T(i18n.BatchCityError, result.City, result.Err))
+			continue
+		}
+		items = append(items, result.Data)
+	}
+
+	if len(items) > 0 {
+		if err := renderer.Render(os.Stdout, items...); err != nil {
+			fail(err)
+		}
+	}
+	if chatty {
+		fmt.Println(printer.T(i18n.BatchSummary, len(items), len(results)))
+	}
+
+	if ctx.Err() != nil {
+		os.Exit(exitInterrupted)
+	}
+	switch {
+	case len(items) == 0:
+		os.Exit(exitError)
+	case len(items) < len(results):
+		os.Exit(exitPartial)
+	}
+}
+
+// readCities читает города по одному на строку; пустые строки и строки с # пропускаются
+func readCities(path string) ([]string, error) {
+	input := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось открыть список городов: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	var cities []string
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cities = append(cities, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения списка городов: %w", err)
+	}
+	return cities, nil
+}
+
+// normalizeLocations проверяет каждое место и приводит его к каноническому виду,
+// чтобы ошибки в координатах обнаруживались до запросов к сервису
+func normalizeLocations(values []string) ([]string, error) {
+	locations := make([]string, len(values))
+	for i, value := range values {
+		location, err := domain.ParseLocation(value)
+		if err != nil {
+			return nil, err
+		}
+		locations[i] = location.String()
+	}
+	return locations, nil
+}
+
+// newProvider создает провайдер по имени из флага --provider; описания погоды
+// open-meteo и openweathermap приходят на языке сообщений
+func newProvider(name string, options ...client.Option) (client.WeatherProvider, error) {
+	lang := string(printer.Lang())
+	switch name {
+	case "wttrin", "wttr.in":
+		return client.NewWttrInProvider(options...), nil
+	case "open-meteo", "openmeteo":
+		return client.NewOpenMeteoProvider(client.WithOpenMeteoLanguage(lang)), nil
+	case "openweathermap", "owm":
+		return client.NewOpenWeatherMapProviderFromEnv(client.WithOpenWeatherMapLanguage(lang))
+	default:
+		return nil, fmt.Errorf("неизвестный провайдер %q: ожидается wttrin, open-meteo или openweathermap", name)
+	}
+}
 
-	city := os.Args[1]
+// newCompositeProvider собирает провайдеры из списка: один используется напрямую,
+// несколько - по очереди при сбоях или параллельно, если задан aggregate
+func newCompositeProvider(names []string, aggregate bool, ttl time.Duration, options ...client.Option) (client.WeatherProvider, error) {
+	var providers []client.NamedProvider
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		provider, err := newProvider(name, options...)
+		if err != nil {
+			return nil, err
+		}
+		if ttl > 0 {
+			provider = withDiskCache(provider, name, ttl)
+		}
+		providers = append(providers, client.NamedProvider{Name: name, Provider: provider})
+	}
 
-	provider := client.NewWttrInProvider()
-	service := client.NewWeatherService(provider)
+	switch {
+	case aggregate:
+		return client.NewAggregateProvider(providers), nil
+	case len(providers) == 1:
+		return providers[0].Provider, nil
+	default:
+		return client.NewFailoverProvider(providers), nil
+	}
+}
 
-	fmt.Printf("Запрашиваю погоду для города: %s\n", city)
+// withDiskCache оборачивает провайдер кешем в каталоге кеша пользователя,
+// у каждого провайдера свой подкаталог. Если каталог недоступен, работаем без кеша.
+func withDiskCache(provider client.WeatherProvider, name string, ttl time.Duration) client.WeatherProvider {
+	dir, err := client.DefaultCacheDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, printer.T(i18n.CacheDisabled, err))
+		return provider
+	}
 
-	data, err := service.GetWeather(city)
+	cache, err := client.NewFileCache(filepath.Join(dir, name))
 	if err != nil {
-		fmt.Printf("❌ Ошибка: %v\n", err)
-		fmt.Println("\nПодсказки:")
-		fmt.Println("- Проверьте название города")
-		fmt.Println("- Попробуйте английское название для международных городов")
-		fmt.Println("- Убедитесь, что есть интернет-соединение")
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, printer.T(i18n.CacheDisabled, err))
+		return provider
 	}
 
-	data.Display()
+	return client.NewCachingProvider(provider, ttl, client.WithCache(cache), client.WithCacheLanguage(string(printer.Lang())))
+}
+
+// fatal завершает работу при ошибке в аргументах или настройке
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, printer.T(i18n.ErrorMessage, err))
+	os.Exit(exitError)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, printer.T(i18n.ErrorMessage, err))
+
+	code, hints := classify(err)
+	if len(hints) > 0 {
+		fmt.Fprintln(os.Stderr, printer.T(i18n.HintsTitle))
+		for _, hint := range hints {
+			fmt.Fprintln(os.Stderr, printer.T(hint))
+		}
+	}
+	os.Exit(code)
+}
+
+// classify подбирает код выхода и подсказки по типу ошибки
+func classify(err error) (int, []i18n.Key) {
+	var parseErr *client.ParseError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted, nil
+	case errors.Is(err, domain.ErrInvalidLocation):
+		return exitError, []i18n.Key{i18n.HintLocation}
+	case errors.Is(err, client.ErrUnsupportedLocation):
+		return exitError, []i18n.Key{i18n.HintLocation, i18n.HintOtherProvider}
+	case errors.Is(err, client.ErrCityNotFound):
+		return exitCityNotFound, []i18n.Key{i18n.HintCheckCity, i18n.HintEnglishName}
+	case errors.Is(err, client.ErrRateLimited):
+		return exitRateLimited, []i18n.Key{i18n.HintRateLimited, i18n.HintUseCache}
+	case errors.Is(err, client.ErrUpstreamUnavailable), errors.Is(err, context.DeadlineExceeded):
+		return exitUnavailable, []i18n.Key{i18n.HintCheckInternet, i18n.HintOtherProvider}
+	case errors.As(err, &parseErr):
+		return exitBadResponse, []i18n.Key{i18n.HintBadResponse, i18n.HintOtherProvider}
+	default:
+		return exitError, []i18n.Key{i18n.HintCheckCity, i18n.HintEnglishName, i18n.HintCheckInternet}
+	}
 }
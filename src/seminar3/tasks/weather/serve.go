@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/server"
+)
+
+// runServe запускает HTTP API: weather serve [--addr :8080] [--provider ...]
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "адрес, на котором слушать HTTP-запросы")
+	flags.String("provider", defaults.Provider, "источники данных через запятую по приоритету: wttrin, open-meteo, openweathermap")
+	flags.Bool("aggregate", defaults.Aggregate, "опросить все источники параллельно и объединить ответы")
+	flags.Duration("cache-ttl", defaults.CacheTTL, "сколько хранить ответы в кеше на диске (0 - без кеша)")
+	flags.Int("concurrency", defaults.Concurrency, "сколько городов запрашивать одновременно")
+	requestTimeout := flags.Duration("request-timeout", 15*time.Second, "срок обработки одного HTTP-запроса")
+	drainDelay := flags.Duration("drain-delay", 5*time.Second, "сколько отвечать \"не готов\" на /readyz перед остановкой")
+	verbose := flags.Bool("verbose", false, "журналировать попытки запросов к сервисам погоды")
+	configPath := addConfigFlags(flags)
+	flags.Parse(args)
+
+	cfg := loadConfig(flags, *configPath)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	provider, err := newCompositeProvider(strings.Split(cfg.Provider, ","), cfg.Aggregate, cfg.CacheTTL, providerOptions(cfg, *verbose)...)
+	if err != nil {
+		fatal(err)
+	}
+	service := client.NewWeatherService(provider, client.WithConcurrency(cfg.Concurrency))
+
+	logger := log.New(os.Stderr, "weather ", log.LstdFlags)
+	api := server.New(service,
+		server.WithLogger(logger),
+		server.WithRequestTimeout(*requestTimeout),
+		server.WithDrainDelay(*drainDelay),
+	)
+
+	if err := api.ListenAndServe(ctx, *addr); err != nil {
+		fatal(err)
+	}
+}
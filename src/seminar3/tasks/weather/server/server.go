@@ -0,0 +1,288 @@
+// Package server отдает WeatherService по HTTP для дашбордов и других сервисов
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+const (
+	defaultRequestTimeout  = 15 * time.Second
+	defaultMaxCities       = 50
+	defaultShutdownTimeout = 10 * time.Second
+	defaultDrainDelay      = 5 * time.Second
+)
+
+// Server HTTP API поверх WeatherService:
+//
+//	GET /weather/{city}         погода в одном городе
+//	GET /weather?city=a&city=b  погода в нескольких городах
+//	GET /healthz                процесс жив
+//	GET /readyz                 сервер готов принимать запросы
+//
+// Известный путь с другим методом получает 405 с заголовком Allow.
+type Server struct {
+	service        *client.WeatherService
+	logger         *log.Logger
+	requestTimeout time.Duration
+	maxCities      int
+	drainDelay     time.Duration
+	handler        http.Handler
+	ready          atomic.Bool
+}
+
+// Option настраивает Server
+type Option func(*Server)
+
+// WithLogger задает журнал запросов; по умолчанию запросы не журналируются
+func WithLogger(logger *log.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithRequestTimeout ограничивает время обработки одного запроса
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.requestTimeout = timeout
+	}
+}
+
+// WithMaxCities ограничивает число городов в одном запросе /weather?city=
+func WithMaxCities(n int) Option {
+	return func(s *Server) {
+		s.maxCities = max(n, 1)
+	}
+}
+
+// WithDrainDelay задает паузу между ответом "не готов" на /readyz и остановкой:
+// за это время балансировщик успевает убрать сервер из ротации. 0 - без паузы
+func WithDrainDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.drainDelay = max(delay, 0)
+	}
+}
+
+func New(service *client.WeatherService, options ...Option) *Server {
+	s := &Server{
+		service:        service,
+		logger:         log.New(io.Discard, "", 0),
+		requestTimeout: defaultRequestTimeout,
+		maxCities:      defaultMaxCities,
+		drainDelay:     defaultDrainDelay,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /weather/{city}", s.handleCity)
+	mux.HandleFunc("GET /weather", s.handleCities)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReady)
+	// catch-all только для GET: на известный путь с другим методом
+	// net/http сам отвечает 405 с заголовком Allow
+	mux.HandleFunc("GET /", s.handleNotFound)
+
+	s.handler = s.logRequests(mux)
+	s.ready.Store(true)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// SetReady переключает ответ /readyz, например перед остановкой
+func (s *Server) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+// ListenAndServe обслуживает addr до отмены ctx, затем перестает отвечать
+// готовностью, ждет drainDelay и дожидается завершения текущих запросов
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Printf("сервер слушает %s", addr)
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("ошибка запуска сервера: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.SetReady(false)
+	if s.drainDelay > 0 {
+		s.logger.Printf("не готов, останавливаю сервер через %v", s.drainDelay)
+		select {
+		case <-time.After(s.drainDelay):
+		case err := <-errCh:
+			return fmt.Errorf("ошибка сервера: %w", err)
+		}
+	}
+	s.logger.Printf("останавливаю сервер")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("ошибка остановки сервера: %w", err)
+	}
+	return nil
+}
+
+// ErrorBody тело ответа с ошибкой
+type ErrorBody struct {
+	Error ErrorInfo `json:"error"`
+}
+
+type ErrorInfo struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CityResult результат по одному городу в ответе /weather?city=
+type CityResult struct {
+	City  string              `json:"city"`
+	Data  *domain.WeatherData `json:"data,omitempty"`
+	Error *ErrorInfo          `json:"error,omitempty"`
+}
+
+type citiesResponse struct {
+	Results []CityResult `json:"results"`
+}
+
+func (s *Server) handleCity(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+	defer cancel()
+
+	data, err := s.service.GetWeather(ctx, r.PathValue("city"))
+	if err != nil {
+		status, info := classify(err)
+		writeJSON(w, status, ErrorBody{Error: info})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+func (s *Server) handleCities(w http.ResponseWriter, r *http.Request) {
+	cities := r.URL.Query()["city"]
+	switch {
+	case len(cities) == 0:
+		writeError(w, http.StatusBadRequest, "bad_request", "укажите хотя бы один параметр city")
+		return
+	case len(cities) > s.maxCities:
+		writeError(w, http.StatusBadRequest, "bad_request", fmt.Sprintf("не больше %d городов за запрос", s.maxCities))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+	defer cancel()
+
+	batch := s.service.GetWeatherBatch(ctx, cities)
+
+	response := citiesResponse{Results: make([]CityResult, len(batch))}
+	for i, result := range batch {
+		response.Results[i] = CityResult{City: result.City, Data: result.Data}
+		if result.Err != nil {
+			_, info := classify(result.Err)
+			response.Results[i].Error = &info
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting down"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("неизвестный путь %s", r.URL.Path))
+}
+
+// classify сопоставляет ошибку сервиса HTTP-статусу и коду ошибки
+func classify(err error) (int, ErrorInfo) {
+	var parseErr *client.ParseError
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidLocation):
+		return http.StatusBadRequest, ErrorInfo{Code: "invalid_location", Message: err.Error()}
+	case errors.Is(err, client.ErrUnsupportedLocation):
+		return http.StatusUnprocessableEntity, ErrorInfo{Code: "unsupported_location", Message: err.Error()}
+	case errors.Is(err, client.ErrCityNotFound):
+		return http.StatusNotFound, ErrorInfo{Code: "city_not_found", Message: err.Error()}
+	case errors.Is(err, client.ErrRateLimited):
+		return http.StatusTooManyRequests, ErrorInfo{Code: "rate_limited", Message: err.Error()}
+	case errors.Is(err, client.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable, ErrorInfo{Code: "upstream_unavailable", Message: err.Error()}
+	case errors.As(err, &parseErr):
+		return http.StatusBadGateway, ErrorInfo{Code: "bad_upstream_response", Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, ErrorInfo{Code: "timeout", Message: err.Error()}
+	case errors.Is(err, context.Canceled):
+		// клиент закрыл соединение, ответ уже никто не прочитает
+		return 499, ErrorInfo{Code: "canceled", Message: err.Error()}
+	default:
+		return http.StatusBadGateway, ErrorInfo{Code: "upstream_error", Message: err.Error()}
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, ErrorBody{Error: ErrorInfo{Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// statusRecorder запоминает код ответа для журнала
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		s.logger.Printf("%s %s %d %v", r.Method, r.URL.RequestURI(), recorder.status, time.Since(start).Round(time.Millisecond))
+	})
+}
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// fakeProvider знает только Moscow и London, "Slow" ждет отмены контекста,
+// остальные специальные названия возвращают соответствующие ошибки
+type fakeProvider struct{}
+
+func (fakeProvider) GetWeather(ctx context.Context, city string) (*domain.WeatherData, error) {
+	switch city {
+	case "Moscow":
+		return &domain.WeatherData{City: "Moscow", Temperature: 5, Humidity: 80, Description: "Cloudy"}, nil
+	case "London":
+		return &domain.WeatherData{City: "London", Temperature: 12, Humidity: 70, Description: "Rain"}, nil
+	case "Slow":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case "Busy":
+		return nil, fmt.Errorf("wttr.in: %w", client.ErrRateLimited)
+	case "Down":
+		return nil, fmt.Errorf("wttr.in: %w", client.ErrUpstreamUnavailable)
+	case "Broken":
+		return nil, &client.ParseError{Field: "current_condition"}
+	case "Flaky":
+		return nil, errors.New("unexpected failure")
+	case "airport:SVO":
+		return nil, fmt.Errorf("open-meteo: %w", client.ErrUnsupportedLocation)
+	case "91,0":
+		return nil, fmt.Errorf("%w: latitude out of range", domain.ErrInvalidLocation)
+	default:
+		return nil, fmt.Errorf("%w: %s", client.ErrCityNotFound, city)
+	}
+}
+
+func newTestServer(t *testing.T, options ...Option) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(New(client.NewWeatherService(fakeProvider{}), options...))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func getJSON(t *testing.T, url string, target any) int {
+	t.Helper()
+
+	response, err := http.Get(url)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(response.Body).Decode(target))
+	return response.StatusCode
+}
+
+func TestGetCity(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	var data domain.WeatherData
+	status := getJSON(t, server.URL+"/weather/Moscow", &data)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, domain.WeatherData{City: "Moscow", Temperature: 5, Humidity: 80, Description: "Cloudy"}, data)
+
+	var body ErrorBody
+	status = getJSON(t, server.URL+"/weather/Atlantis", &body)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "city_not_found", body.Error.Code)
+	assert.Contains(t, body.Error.Message, "Atlantis")
+}
+
+func TestGetCityErrorMapping(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	tests := []struct {
+		city   string
+		status int
+		code   string
+	}{
+		{"Atlantis", http.StatusNotFound, "city_not_found"},
+		{"Busy", http.StatusTooManyRequests, "rate_limited"},
+		{"Down", http.StatusServiceUnavailable, "upstream_unavailable"},
+		{"Broken", http.StatusBadGateway, "bad_upstream_response"},
+		{"Flaky", http.StatusBadGateway, "upstream_error"},
+		{"airport:SVO", http.StatusUnprocessableEntity, "unsupported_location"},
+		{"91,0", http.StatusBadRequest, "invalid_location"},
+	}
+
+	for _, tt := range tests {
+		var body ErrorBody
+		status := getJSON(t, server.URL+"/weather/"+tt.city, &body)
+		assert.Equal(t, tt.status, status, tt.city)
+		assert.Equal(t, tt.code, body.Error.Code, tt.city)
+	}
+}
+
+func TestGetCityTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, WithRequestTimeout(20*time.Millisecond))
+
+	var body ErrorBody
+	status := getJSON(t, server.URL+"/weather/Slow", &body)
+	assert.Equal(t, http.StatusGatewayTimeout, status)
+	assert.Equal(t, "timeout", body.Error.Code)
+}
+
+func TestGetCities(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	var response citiesResponse
+	status := getJSON(t, server.URL+"/weather?city=Moscow&city=Atlantis&city=London", &response)
+	assert.Equal(t, http.StatusOK, status)
+	require.Len(t, response.Results, 3)
+
+	assert.Equal(t, "Moscow", response.Results[0].City)
+	require.NotNil(t, response.Results[0].Data)
+	assert.Equal(t, 5.0, response.Results[0].Data.Temperature)
+	assert.Nil(t, response.Results[0].Error)
+
+	assert.Nil(t, response.Results[1].Data)
+	require.NotNil(t, response.Results[1].Error)
+	assert.Equal(t, "city_not_found", response.Results[1].Error.Code)
+
+	assert.Equal(t, "London", response.Results[2].Data.City)
+}
+
+func TestBadRequests(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, WithMaxCities(2))
+
+	tests := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/weather", http.StatusBadRequest, "bad_request"},
+		{"/weather?city=a&city=b&city=c", http.StatusBadRequest, "bad_request"},
+		{"/unknown", http.StatusNotFound, "not_found"},
+	}
+
+	for _, tt := range tests {
+		var body ErrorBody
+		status := getJSON(t, server.URL+tt.path, &body)
+		assert.Equal(t, tt.status, status, tt.path)
+		assert.Equal(t, tt.code, body.Error.Code, tt.path)
+		assert.NotEmpty(t, body.Error.Message, tt.path)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	for _, path := range []string{"/weather", "/weather/Moscow", "/readyz"} {
+		response, err := http.Post(server.URL+path, "application/json", nil)
+		require.NoError(t, err)
+		response.Body.Close()
+
+		assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode, path)
+		assert.Contains(t, response.Header.Get("Allow"), http.MethodGet, path)
+	}
+}
+
+func TestHealthAndReadiness(t *testing.T) {
+	t.Parallel()
+
+	handler := New(client.NewWeatherService(fakeProvider{}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var body map[string]string
+	assert.Equal(t, http.StatusOK, getJSON(t, server.URL+"/healthz", &body))
+	assert.Equal(t, http.StatusOK, getJSON(t, server.URL+"/readyz", &body))
+
+	handler.SetReady(false)
+	assert.Equal(t, http.StatusServiceUnavailable, getJSON(t, server.URL+"/readyz", &body))
+	assert.Equal(t, http.StatusOK, getJSON(t, server.URL+"/healthz", &body))
+}
+
+func TestRequestLogging(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	handler := New(client.NewWeatherService(fakeProvider{}), WithLogger(log.New(&logs, "", 0)))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/weather/Atlantis", nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Contains(t, logs.String(), "GET /weather/Atlantis 404")
+}
+
+func TestListenAndServeGracefulShutdown(t *testing.T) {
+	t.Parallel()
+
+	// свободный порт: занимаем и сразу освобождаем
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := New(client.NewWeatherService(fakeProvider{}), WithDrainDelay(300*time.Millisecond))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- handler.ListenAndServe(ctx, addr)
+	}()
+
+	require.Eventually(t, func() bool {
+		response, err := http.Get("http://" + addr + "/healthz")
+		if err != nil {
+			return false
+		}
+		response.Body.Close()
+		return response.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	// во время паузы сервер еще отвечает, но уже не готов
+	require.Eventually(t, func() bool {
+		response, err := http.Get("http://" + addr + "/readyz")
+		if err != nil {
+			return false
+		}
+		response.Body.Close()
+		return response.StatusCode == http.StatusServiceUnavailable
+	}, 250*time.Millisecond, 10*time.Millisecond)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	assert.False(t, handler.ready.Load())
+}
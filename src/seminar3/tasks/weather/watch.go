@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"example/src/seminar3/tasks/weather/alert"
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/i18n"
+)
+
+// stringList флаг, который можно указать несколько раз
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runWatch следит за погодой и присылает оповещения по правилам:
+// weather watch --rule "temperature < -15" --notify webhook:https://example.com/hook Moscow
+func runWatch(args []string) {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	rulesFile := flags.String("rules", "", "YAML-файл с правилами (см. alert.LoadRules)")
+	var rules, notify stringList
+	flags.Var(&rules, "rule", `правило "поле оператор порог[@город,...]", можно несколько: --rule "wind_speed > 50"`)
+	flags.Var(&notify, "notify", "куда слать оповещения, можно несколько: stdout, webhook:URL, file:ПУТЬ (по умолчанию stdout)")
+	interval := flags.Duration("interval", 10*time.Minute, "период опроса")
+	once := flags.Bool("once", false, "проверить правила один раз и выйти")
+	flags.String("provider", defaults.Provider, "источники данных через запятую по приоритету")
+	flags.Bool("aggregate", defaults.Aggregate, "опросить все источники параллельно и объединить ответы")
+	flags.Duration("cache-ttl", defaults.CacheTTL, "сколько хранить ответы в кеше на диске (0 - без кеша)")
+	flags.Int("concurrency", defaults.Concurrency, "сколько городов запрашивать одновременно")
+	flags.Bool("history", defaults.History, "сохранять полученную погоду в историю")
+	flags.String("history-file", defaults.HistoryFile, "файл истории (по умолчанию в каталоге настроек пользователя)")
+	flags.String("lang", defaults.Lang, "язык оповещений: ru или en (по умолчанию из LANG)")
+	verbose := flags.Bool("verbose", false, "выводить ход запросов и повторные попытки в stderr")
+	configPath := addConfigFlags(flags)
+	flags.Parse(args)
+
+	cfg := loadConfig(flags, *configPath)
+
+	engine, err := newEngine(*rulesFile, rules)
+	if err != nil {
+		fatal(err)
+	}
+	notifier, err := newNotifier(notify)
+	if err != nil {
+		fatal(err)
+	}
+
+	// без городов в аргументах следим за городами из правил
+	cities := flags.Args()
+	if len(cities) == 0 {
+		cities = engine.Cities()
+	}
+	if len(cities) == 0 {
+		fatal(errors.New("укажите города в аргументах или в правилах"))
+	}
+	if cities, err = normalizeLocations(cities); err != nil {
+		fail(err)
+	}
+
+	provider, err := newCompositeProvider(strings.Split(cfg.Provider, ","), cfg.Aggregate, cfg.CacheTTL, providerOptions(cfg, *verbose)...)
+	if err != nil {
+		fatal(err)
+	}
+	serviceOptions := []client.ServiceOption{client.WithConcurrency(cfg.Concurrency)}
+	if cfg.History {
+		serviceOptions = append(serviceOptions, historyOptions(cfg.HistoryFile)...)
+	}
+	service := client.NewWeatherService(provider, serviceOptions...)
+
+	watcher := alert.NewWatcher(service, engine, notifier, cities,
+		alert.WithInterval(*interval),
+		alert.WithErrorHandler(func(err error) {
+			fmt.Fprintln(os.Stderr, printer.T(i18n.ErrorMessage, err))
+		}),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		watcher.Poll(ctx)
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, printer.T(i18n.WatchStarted, len(cities), len(engine.Rules()), *interval))
+	if err := watcher.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		fatal(err)
+	}
+}
+
+// newEngine собирает правила из файла и из флагов --rule
+func newEngine(path string, expressions []string) (*alert.Engine, error) {
+	var rules []alert.Rule
+	if path != "" {
+		loaded, err := alert.LoadRules(path)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, loaded...)
+	}
+	for _, expression := range expressions {
+		rule, err := alert.ParseRule(expression)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil, errors.New("не задано ни одного правила: используйте --rule или --rules")
+	}
+	return alert.NewEngine(rules)
+}
+
+// newNotifier создает получателей оповещений по описаниям из флагов --notify
+func newNotifier(targets []string) (alert.Notifier, error) {
+	if len(targets) == 0 {
+		targets = []string{"stdout"}
+	}
+
+	var notifiers alert.Notifiers
+	for _, target := range targets {
+		kind, value, _ := strings.Cut(target, ":")
+		switch {
+		case target == "stdout":
+			notifiers = append(notifiers, alert.NewWriterNotifier(os.Stdout, printer))
+		case kind == "webhook" && value != "":
+			notifiers = append(notifiers, alert.NewWebhookNotifier(value))
+		case kind == "file" && value != "":
+			notifiers = append(notifiers, alert.NewFileNotifier(value))
+		default:
+			return nil, fmt.Errorf("неизвестный получатель оповещений %q: ожидается stdout, webhook:URL или file:ПУТЬ", target)
+		}
+	}
+	return notifiers, nil
+}